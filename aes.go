@@ -0,0 +1,104 @@
+package go_libs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strconv"
+)
+
+const aes256KeySize = 32 // AES-256 key length in bytes
+
+// sizes of the standard AES-GCM nonce and tag, as used by EncryptAES256
+const (
+	aesGCMNonceSize = 12
+	aesGCMTagSize   = 16
+)
+
+// aesFormatV1 is the version tag of the current EncryptAES256V format: version || nonce || ciphertext || tag
+const aesFormatV1 byte = 1
+
+// newAES256GCM verifies the key length and returns an AES-256 cipher in GCM mode.
+func newAES256GCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != aes256KeySize {
+		return nil, errors.New("key must be " + strconv.Itoa(aes256KeySize) + " bytes, but is " + strconv.Itoa(len(key)) + " bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// GenerateAESKey returns a random 32-byte key for EncryptAES256, read from crypto/rand.
+func GenerateAESKey() ([]byte, error) {
+	key := make([]byte, aes256KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return key, nil
+}
+
+// EncryptAES256 encrypts the plaintext with AES-256 in GCM mode. The key must be exactly 32 bytes.
+// A random 12-byte nonce is created for each call and prepended to the result, so the output
+// layout is nonce || ciphertext || tag.
+func EncryptAES256(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newAES256GCM(key)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":nonce creation:" + err.Error())
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// DecryptAES256 decrypts a ciphertext created by EncryptAES256. The GCM tag is verified, so a
+// modified ciphertext or a wrong key results in an authentication error instead of garbage.
+func DecryptAES256(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newAES256GCM(key)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New(CurrentFunctionName() + ":ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":authentication failed, ciphertext modified or wrong key")
+	}
+	return plaintext, nil
+}
+
+// EncryptAES256V works like EncryptAES256 but prepends a one-byte format version. If the layout
+// of the ciphertexts changes in the future, stored data can still be decrypted by DecryptAES256V.
+func EncryptAES256V(key, plaintext []byte) ([]byte, error) {
+	ciphertext, err := EncryptAES256(key, plaintext)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return append([]byte{aesFormatV1}, ciphertext...), nil
+}
+
+// DecryptAES256V decrypts a ciphertext created by EncryptAES256V. It dispatches on the version byte.
+func DecryptAES256V(key, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, errors.New(CurrentFunctionName() + ":ciphertext is empty")
+	}
+	switch ciphertext[0] {
+	case aesFormatV1:
+		plaintext, err := DecryptAES256(key, ciphertext[1:])
+		if err != nil {
+			return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+		}
+		return plaintext, nil
+	default:
+		return nil, errors.New(CurrentFunctionName() + ":unsupported format version " + strconv.Itoa(int(ciphertext[0])))
+	}
+}
+
+// EOF
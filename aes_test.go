@@ -0,0 +1,91 @@
+package go_libs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAES256RoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	plaintext := []byte("AES-256-GCM plaintext")
+	ciphertext, err := EncryptAES256(key, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptAES256 failed:%s\n", err)
+	}
+	if len(ciphertext) != aesGCMNonceSize+len(plaintext)+aesGCMTagSize {
+		t.Errorf("Ciphertext length error, is:%d, expected:%d\n", len(ciphertext), aesGCMNonceSize+len(plaintext)+aesGCMTagSize)
+	}
+	decrypted, err := DecryptAES256(key, ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptAES256 failed:%s\n", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Round-trip error, is:%s, expected:%s\n", decrypted, plaintext)
+	}
+	if _, err := EncryptAES256(key[:16], plaintext); err == nil || !strings.Contains(err.Error(), "32 bytes") {
+		t.Errorf("Short key error, is:%v, expected an error about the key length\n", err)
+	}
+}
+
+func TestAES256TamperedCiphertext(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	ciphertext, err := EncryptAES256(key, []byte("do not modify"))
+	if err != nil {
+		t.Fatalf("EncryptAES256 failed:%s\n", err)
+	}
+	ciphertext[aesGCMNonceSize] ^= 0x01
+	if _, err := DecryptAES256(key, ciphertext); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("Tampered ciphertext error, is:%v, expected an authentication failure\n", err)
+	}
+}
+
+func TestAES256VRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	plaintext := []byte("versioned plaintext")
+	ciphertext, err := EncryptAES256V(key, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptAES256V failed:%s\n", err)
+	}
+	if ciphertext[0] != 1 {
+		t.Errorf("Version tag error, is:%d, expected:1\n", ciphertext[0])
+	}
+	decrypted, err := DecryptAES256V(key, ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptAES256V failed:%s\n", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Round-trip error, is:%s, expected:%s\n", decrypted, plaintext)
+	}
+}
+
+func TestAES256VUnknownVersion(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	ciphertext, err := EncryptAES256V(key, []byte("msg"))
+	if err != nil {
+		t.Fatalf("EncryptAES256V failed:%s\n", err)
+	}
+	ciphertext[0] = 99
+	if _, err := DecryptAES256V(key, ciphertext); err == nil {
+		t.Errorf("Expected an error for an unknown version\n")
+	}
+}
+
+func TestGenerateAESKey(t *testing.T) {
+	key1, err := GenerateAESKey()
+	if err != nil {
+		t.Fatalf("GenerateAESKey failed:%s\n", err)
+	}
+	key2, err := GenerateAESKey()
+	if err != nil {
+		t.Fatalf("GenerateAESKey failed:%s\n", err)
+	}
+	if len(key1) != 32 {
+		t.Errorf("Key length error, is:%d, expected:32\n", len(key1))
+	}
+	if bytes.Equal(key1, key2) {
+		t.Errorf("Two generated keys are equal\n")
+	}
+}
+
+// EOF
@@ -0,0 +1,71 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+const pemTypeCertificate = "CERTIFICATE"
+
+// CreateSelfSignedCert creates a self-signed TLS server certificate for the key and returns it in PEM
+// format. The subject is the common name cn. The dnsNames are added as subject alternative names, IP
+// addresses like 127.0.0.1 as IP SANs. The certificate has a random 128-bit serial number and is valid
+// from now for validFor. The key usage is digital signature and key encipherment.
+func CreateSelfSignedCert(priv *rsa.PrivateKey, cn string, dnsNames []string, validFor time.Duration) ([]byte, error) {
+	if priv == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if validFor <= 0 {
+		return nil, errors.New(CurrentFunctionName() + ":validity must be positive")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":serial number:" + err.Error())
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             now,
+		NotAfter:              now.Add(validFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, name := range dnsNames {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}), nil
+}
+
+// WriteCertificate writes the PEM-encoded certificate, e.g. from CreateSelfSignedCert, to the file. An
+// error is returned if certPEM does not start with a CERTIFICATE block.
+func WriteCertificate(file *os.File, certPEM []byte) error {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != pemTypeCertificate {
+		return fmt.Errorf("%s:%w:no PEM block containing a certificate", CurrentFunctionName(), ErrParse)
+	}
+	if _, err := file.Write(certPEM); err != nil {
+		return errors.New(CurrentFunctionName() + ":writeFile:" + err.Error())
+	}
+	return nil
+}
+
+// EOF
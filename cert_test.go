@@ -0,0 +1,59 @@
+package go_libs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateSelfSignedCert(t *testing.T) {
+	key := testPrivateKey(t)
+	certPEM, err := CreateSelfSignedCert(key, "localhost", []string{"localhost", "127.0.0.1"}, time.Hour)
+	if err != nil {
+		t.Fatalf("CreateSelfSignedCert failed:%s\n", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatalf("Certificate is not PEM encoded\n")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificate failed:%s\n", err)
+	}
+	if err := cert.VerifyHostname("localhost"); err != nil {
+		t.Errorf("DNS SAN error:%s\n", err)
+	}
+	if err := cert.VerifyHostname("127.0.0.1"); err != nil {
+		t.Errorf("IP SAN error:%s\n", err)
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		t.Errorf("Certificate is not self-signed:%s\n", err)
+	}
+	if cert.KeyUsage != x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment {
+		t.Errorf("Key usage error, is:%v\n", cert.KeyUsage)
+	}
+	if _, err := CreateSelfSignedCert(nil, "localhost", nil, time.Hour); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "cert.pem"))
+	if err != nil {
+		t.Fatalf("Create failed:%s\n", err)
+	}
+	defer file.Close()
+	if err := WriteCertificate(file, certPEM); err != nil {
+		t.Fatalf("WriteCertificate failed:%s\n", err)
+	}
+	if written, _ := os.ReadFile(file.Name()); string(written) != string(certPEM) {
+		t.Errorf("Written certificate differs\n")
+	}
+	if err := WriteCertificate(file, []byte("no certificate")); !errors.Is(err, ErrParse) {
+		t.Errorf("Invalid certificate error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+// EOF
@@ -0,0 +1,44 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SignCookie signs the value with RSA-PSS and returns the cookie value base64url(value).base64url(sig),
+// both without padding, so it can be used in a cookie without further escaping.
+func SignCookie(key *rsa.PrivateKey, value []byte) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	sig, err := SignPSSByteArray(key, Sha256bytes2bytes(value))
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(value) + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyCookie verifies a cookie value created by SignCookie and returns the original value.
+func VerifyCookie(pub *rsa.PublicKey, cookie string) ([]byte, error) {
+	parts := strings.Split(cookie, ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%s:%w:cookie must consist of value and signature", CurrentFunctionName(), ErrParse)
+	}
+	value, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:Error, decoding base64url value", CurrentFunctionName(), ErrParse)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:Error, decoding base64url signature", CurrentFunctionName(), ErrParse)
+	}
+	if err := VerifyPSSByteArray(pub, sig, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// EOF
@@ -0,0 +1,42 @@
+package go_libs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestSignCookieRoundTrip(t *testing.T) {
+	key := testPrivateKey(t)
+	value := []byte(`{"user":"alice","role":"admin"}`)
+	cookie, err := SignCookie(key, value)
+	if err != nil {
+		t.Fatalf("SignCookie failed:%s\n", err)
+	}
+	verified, err := VerifyCookie(&key.PublicKey, cookie)
+	if err != nil {
+		t.Fatalf("VerifyCookie failed:%s\n", err)
+	}
+	if !bytes.Equal(verified, value) {
+		t.Errorf("Value error, is:%s, expected:%s\n", verified, value)
+	}
+}
+
+func TestVerifyCookieTampered(t *testing.T) {
+	key := testPrivateKey(t)
+	cookie, err := SignCookie(key, []byte(`{"user":"alice","role":"user"}`))
+	if err != nil {
+		t.Fatalf("SignCookie failed:%s\n", err)
+	}
+	sig := cookie[strings.Index(cookie, ".")+1:]
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"user":"alice","role":"admin"}`)) + "." + sig
+	if _, err := VerifyCookie(&key.PublicKey, tampered); err == nil {
+		t.Errorf("Tampered cookie should be rejected\n")
+	}
+	if _, err := VerifyCookie(&key.PublicKey, "no-signature"); err == nil {
+		t.Errorf("Cookie without signature should be rejected\n")
+	}
+}
+
+// EOF
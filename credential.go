@@ -0,0 +1,45 @@
+package go_libs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+// maxCredentialNameLength is the maximum length of a systemd credential name, it must be a valid file name.
+const maxCredentialNameLength = 255
+
+// checkCredentialName checks that name can be used as a systemd credential name.
+func checkCredentialName(name string) error {
+	switch {
+	case name == "", name == ".", name == "..":
+		return errors.New("invalid credential name '" + name + "'")
+	case len(name) > maxCredentialNameLength:
+		return errors.New("credential name too long")
+	case strings.ContainsAny(name, "/\x00"):
+		return errors.New("credential name must not contain / or NUL")
+	}
+	return nil
+}
+
+// CreateRSAKeyPairCredential creates an RSA 4096-bit key-pair and returns the private key as a PKCS#1
+// PEM blob, ready to be stored as the systemd credential name. The blob is meant to be encrypted on the
+// target host, e.g. with systemd-creds encrypt --name=<name> - /etc/credstore.encrypted/<name>, and
+// loaded by the service with LoadCredentialEncrypted=. The service can then read the key with
+// LoadPrivateKey from $CREDENTIALS_DIRECTORY/<name>.
+func CreateRSAKeyPairCredential(name string) (credBlob []byte, err error) {
+	if err := checkCredentialName(name); err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	privateKey, _, err := CreateRSAKeyPair()
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  pemTypePKCS1PrivateKey,
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), nil
+}
+
+// EOF
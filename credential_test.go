@@ -0,0 +1,26 @@
+package go_libs
+
+import (
+	"testing"
+)
+
+func TestCreateRSAKeyPairCredential(t *testing.T) {
+	blob, err := CreateRSAKeyPairCredential("signing-key")
+	if err != nil {
+		t.Fatalf("CreateRSAKeyPairCredential failed:%s\n", err)
+	}
+	key, err := Pem2RsaPrivateKey(blob)
+	if err != nil {
+		t.Fatalf("Pem2RsaPrivateKey of the credential failed:%s\n", err)
+	}
+	if key.N.BitLen() != bitSize {
+		t.Errorf("Key size error, is:%d, expected:%d\n", key.N.BitLen(), bitSize)
+	}
+	for _, name := range []string{"", "..", "dir/key"} {
+		if _, err := CreateRSAKeyPairCredential(name); err == nil {
+			t.Errorf("Credential name '%s' should be rejected\n", name)
+		}
+	}
+}
+
+// EOF
@@ -1,20 +1,28 @@
 package go_libs
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 const bitSize = 4096 // RSA keysize
 
+const publicKeyFileSuffix = ".pub" // suffix of the public key file next to the private key file
+
 // Sha256bytes2bytes converts a byte sequence into a SHA-256-based digest of it.
 // The output for this application is the same on the commadn line with:
 // curl -q localhost:8888 | jq -c .Data | tr -d '\n' | shasum -a256
@@ -29,7 +37,54 @@ func Sha256bytes2bytes(bytes []byte) []byte {
 	return msgHash.Sum(nil)
 }
 
-// SignPSSByteArray returns a signature for the given digest or returns an error
+// Sha256Hex returns the SHA-256 digest of the bytes as lowercase hex string, see Sha256bytes2bytes.
+// This is the same output as shasum -a256.
+func Sha256Hex(bytes []byte) string {
+	return fmt.Sprintf("%x", Sha256bytes2bytes(bytes))
+}
+
+// Sha256Reader streams the content of the reader through SHA-256 and returns the digest. It returns
+// the same digest as Sha256bytes2bytes for the same content without keeping the content in memory,
+// e.g. for large files or HTTP bodies.
+func Sha256Reader(r io.Reader) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New(CurrentFunctionName() + ":Error, reader is nil")
+	}
+	msgHash := sha256.New()
+	if _, err := io.Copy(msgHash, r); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return msgHash.Sum(nil), nil
+}
+
+// Sha256File streams the content of the file through SHA-256 and returns the digest, see Sha256Reader.
+// The file is not read into memory.
+func Sha256File(filename string) ([]byte, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("%s:opening file:%w", CurrentFunctionName(), err)
+	}
+	defer file.Close()
+	digest, err := Sha256Reader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s:%w", CurrentFunctionName(), filename, err)
+	}
+	return digest, nil
+}
+
+// Sha256FileHex works like Sha256File, but returns the digest as lowercase hex string. This is the
+// same output as shasum -a256.
+func Sha256FileHex(filename string) (string, error) {
+	digest, err := Sha256File(filename)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", digest), nil
+}
+
+// SignPSSByteArray returns an RSA-PSS signature (SHA-256) for the given digest or returns an error. PSS
+// is the recommended scheme, use SignByteArrayPKCS1v15 only for partners which cannot verify PSS. See
+// FingerprintInErrors to identify the key in the error.
 func SignPSSByteArray(key *rsa.PrivateKey, digest []byte) ([]byte, error) {
 	var opts rsa.PSSOptions
 	opts.SaltLength = rsa.PSSSaltLengthAuto
@@ -38,7 +93,7 @@ func SignPSSByteArray(key *rsa.PrivateKey, digest []byte) ([]byte, error) {
 	}
 	signature, err := rsa.SignPSS(rand.Reader, key, crypto.SHA256, digest, &opts)
 	if err != nil {
-		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+		return nil, signingError(CurrentFunctionName(), key, err)
 	}
 	return signature, nil
 }
@@ -54,32 +109,92 @@ func SignPSSByteArray2Base64(key *rsa.PrivateKey, digest []byte) (string, error)
 
 // VerifyPSSByteArray verifies a digital signature (digest). If no error is returned,
 // then the verification was successful. Furthermore, it recalculates the digest of the
-// message. It should result in the same digest as the digitally signed one.
+// message. It should result in the same digest as the digitally signed one. The error reports
+// the reason of a failure, see VerifyDetailed.
 func VerifyPSSByteArray(key *rsa.PublicKey, digest []byte, msg []byte) error {
+	result := VerifyDetailed(key, digest, msg)
+	if result.RecomputedDigest != nil {
+		CondDebugln(CurrentFunctionName() + ", recalculated digest for msg: " + fmt.Sprintf("%x", result.RecomputedDigest))
+	}
+	if result.Err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), result.Err)
+	}
+	return nil
+}
+
+// VerifyDigestSignature verifies an RSA-PSS signature (SHA-256) against an already computed digest, e.g.
+// from Sha256Reader or Sha256File for large payloads. The digest is not recalculated. If no error is
+// returned, then the verification was successful.
+func VerifyDigestSignature(key *rsa.PublicKey, digest, signature []byte) error {
 	var opts rsa.PSSOptions
 	opts.SaltLength = rsa.PSSSaltLengthAuto
 	if key == nil {
-		return errors.New(CurrentFunctionName() + ":Error, public key is nil")
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
 	}
-	if digest == nil {
-		return errors.New(CurrentFunctionName() + ":Error, digest is nil")
+	if signature == nil {
+		return fmt.Errorf("%s:%w:Error, signature is nil", CurrentFunctionName(), ErrParse)
 	}
-	plaintestDigest := Sha256bytes2bytes(msg)
-	CondDebugln(CurrentFunctionName() + ", recalculated digest for msg: " + fmt.Sprintf("%x", plaintestDigest))
-	return rsa.VerifyPSS(key, crypto.SHA256, plaintestDigest, digest, &opts)
+	if len(digest) != crypto.SHA256.Size() {
+		return fmt.Errorf("%s:%w:Error, digest length %d is not a SHA-256 digest", CurrentFunctionName(), ErrParse, len(digest))
+	}
+	return rsa.VerifyPSS(key, crypto.SHA256, digest, signature, &opts)
 }
 
 // VerifyPSSBase64String accepts a base64 encoded string as the signature.
-// It decodes the signature and calls VerifyByteArray.
+// It decodes the signature and verifies it like VerifyPSSByteArray, see VerifyDetailedBase64String.
 func VerifyPSSBase64String(key *rsa.PublicKey, b64 string, msg string) error {
-	signatureByte, err := base64.StdEncoding.DecodeString(b64)
+	if err := VerifyDetailedBase64String(key, b64, msg).Err; err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// SignPSSByteArray2Base64URL works like SignPSSByteArray2Base64, but returns the signature as unpadded
+// base64url (RFC 4648 section 5). It can be put into query strings and headers without percent-encoding.
+func SignPSSByteArray2Base64URL(key *rsa.PrivateKey, digest []byte) (string, error) {
+	sig, err := SignPSSByteArray(key, digest)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyPSSBase64URLString accepts an unpadded base64url encoded string as the signature, as created by
+// SignPSSByteArray2Base64URL. It decodes the signature and calls VerifyPSSByteArray.
+func VerifyPSSBase64URLString(key *rsa.PublicKey, b64 string, msg string) error {
+	signatureByte, err := base64.RawURLEncoding.DecodeString(b64)
 	if err != nil {
-		return errors.New(CurrentFunctionName() + ":Error, decoding base64 string")
+		return fmt.Errorf("%s:%w:Error, decoding base64url string", CurrentFunctionName(), ErrParse)
 	}
 	return VerifyPSSByteArray(key, signatureByte, []byte(msg))
 }
 
-// Sign115ByteArray returns a signature for the given digest or returns an error
+// VerifyWithDecoder works like VerifyPSSBase64String, but the signature is decoded by the function
+// decode, e.g. hex.DecodeString, base32.StdEncoding.DecodeString, or a custom transport encoding.
+func VerifyWithDecoder(pub *rsa.PublicKey, msg string, sigInput string, decode func(string) ([]byte, error)) error {
+	if decode == nil {
+		return errors.New(CurrentFunctionName() + ":Error, decoder is nil")
+	}
+	sig, err := decode(sigInput)
+	if err != nil {
+		return fmt.Errorf("%s:%w:Error, decoding signature:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	return VerifyPSSByteArray(pub, sig, []byte(msg))
+}
+
+// VerifyTrimmed verifies the RSA-PSS signature for the message as-is and, if this fails, for the message
+// with one trailing newline removed. This tolerates the common mismatch when one side strips the newline
+// (tr -d '\n', see Sha256bytes2bytes) and the other side does not.
+func VerifyTrimmed(pub *rsa.PublicKey, msg string, sig []byte) error {
+	err := VerifyPSSByteArray(pub, sig, []byte(msg))
+	if err == nil || !strings.HasSuffix(msg, "\n") {
+		return err
+	}
+	return VerifyPSSByteArray(pub, sig, []byte(strings.TrimSuffix(msg, "\n")))
+}
+
+// Sign115ByteArray returns a deterministic RSASSA-PKCS1-v1_5 signature (SHA-256) for the given digest or
+// returns an error.
 func Sign115ByteArray(key *rsa.PrivateKey, digest []byte) ([]byte, error) {
 	//var opts rsa.PSSOptions
 	//opts.SaltLength = rsa.PSSSaltLengthAuto
@@ -88,7 +203,7 @@ func Sign115ByteArray(key *rsa.PrivateKey, digest []byte) ([]byte, error) {
 	}
 	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
 	if err != nil {
-		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+		return nil, signingError(CurrentFunctionName(), key, err)
 	}
 	return signature, nil
 }
@@ -107,10 +222,10 @@ func Sign115ByteArray2Base64(key *rsa.PrivateKey, digest []byte) (string, error)
 // message. It should result in the same digest as the digitally signed one.
 func Verify115ByteArray(key *rsa.PublicKey, digest []byte, msg []byte) error {
 	if key == nil {
-		return errors.New(CurrentFunctionName() + ":Error, public key is nil")
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
 	}
 	if digest == nil {
-		return errors.New(CurrentFunctionName() + ":Error, digest is nil")
+		return fmt.Errorf("%s:%w:Error, digest is nil", CurrentFunctionName(), ErrParse)
 	}
 	plaintestDigest := Sha256bytes2bytes(msg)
 	CondDebugln(CurrentFunctionName() + ", recalculated digest for msg: " + fmt.Sprintf("%x", plaintestDigest))
@@ -122,79 +237,300 @@ func Verify115ByteArray(key *rsa.PublicKey, digest []byte, msg []byte) error {
 func Verify115Base64String(key *rsa.PublicKey, b64 string, msg string) error {
 	signatureByte, err := base64.StdEncoding.DecodeString(b64)
 	if err != nil {
-		return errors.New(CurrentFunctionName() + ":Error, decoding base64 string")
+		return fmt.Errorf("%s:%w:Error, decoding base64 string", CurrentFunctionName(), ErrParse)
 	}
 	return Verify115ByteArray(key, signatureByte, []byte(msg))
 }
 
+// SignByteArrayPKCS1v15 returns a deterministic RSASSA-PKCS1-v1_5 signature (SHA-256) of the digest for
+// legacy partners which cannot verify RSA-PSS. It is the same as Sign115ByteArray. Prefer
+// SignPSSByteArray otherwise.
+func SignByteArrayPKCS1v15(key *rsa.PrivateKey, digest []byte) ([]byte, error) {
+	return Sign115ByteArray(key, digest)
+}
+
+// VerifyByteArrayPKCS1v15 verifies an RSASSA-PKCS1-v1_5 signature (SHA-256) of the message, as created by
+// SignByteArrayPKCS1v15. It is the same as Verify115ByteArray. If no error is returned, then the
+// verification was successful.
+func VerifyByteArrayPKCS1v15(key *rsa.PublicKey, digest []byte, msg []byte) error {
+	return Verify115ByteArray(key, digest, msg)
+}
+
 // =======================================================================================
 // = Key Loading and Signing
 
-// Pem2RsaPrivateKey load a PEM-encoded RSA private key from a buffer. The function does not try
-// to read multiple keys from the byte array. Only the first PEM block is processed.
-func Pem2RsaPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+// PEM block types of RSA private keys
+const (
+	pemTypePKCS1PrivateKey = "RSA PRIVATE KEY" // PKCS#1
+	pemTypePKCS8PrivateKey = "PRIVATE KEY"     // PKCS#8
+	pemTypePKCS1PublicKey  = "RSA PUBLIC KEY"  // PKCS#1
+	pemTypePKIXPublicKey   = "PUBLIC KEY"      // PKIX/SPKI, as written by WriteRsaPublicKey
+)
+
+// pem2RsaPrivateKeyWithType parses the first PEM block as a PKCS#1 or PKCS#8 RSA private key and also
+// returns the type of the PEM block. The CRT values of the key are precomputed for faster signing.
+func pem2RsaPrivateKeyWithType(der []byte) (*rsa.PrivateKey, string, error) {
 	block, _ := pem.Decode(der)
-	if block == nil || block.Type != "RSA PRIVATE KEY" {
-		return nil, errors.New(CurrentFunctionName() + ":failed to decode PEM block containing private key")
+	if block != nil && block.Type == pemTypeEncryptedPKCS8PrivateKey {
+		return nil, block.Type, errors.New("key is encrypted, a password is required, see ParsePrivateKeyWithPassword")
+	}
+	if block == nil || (block.Type != pemTypePKCS1PrivateKey && block.Type != pemTypePKCS8PrivateKey) {
+		return nil, "", errors.New("failed to decode PEM block containing private key")
+	}
+	if _, ok := block.Headers["DEK-Info"]; ok {
+		return nil, block.Type, errors.New("key is encrypted, a password is required, see ParsePrivateKeyWithPassword")
+	}
+	defer zeroBytes(block.Bytes) // the parsed key does not reference the DER
+	return parseRsaPrivateKeyBlock(block.Type, block.Bytes)
+}
+
+// parseRsaPrivateKeyBlock parses the DER of a PKCS#1 or PKCS#8 PEM block. The x509 parsers already
+// precompute the CRT values of the key for faster signing.
+func parseRsaPrivateKeyBlock(pemType string, der []byte) (*rsa.PrivateKey, string, error) {
+	if pemType == pemTypePKCS1PrivateKey {
+		priv, err := x509.ParsePKCS1PrivateKey(der)
+		if err != nil {
+			return nil, pemType, errors.New("failed to parse PEM block:" + err.Error())
+		}
+		return priv, pemType, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, pemType, errors.New("failed to parse PEM block:" + err.Error())
+	}
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, pemType, errors.New("Unsupported private key type, not RSA.")
 	}
-	pub, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	return priv, pemType, nil
+}
+
+// Pem2RsaPrivateKey load a PEM-encoded RSA private key from a buffer. PKCS#1 (RSA PRIVATE KEY) and
+// PKCS#8 (PRIVATE KEY) blocks are supported. The function does not try to read multiple keys from
+// the byte array. Only the first PEM block is processed.
+func Pem2RsaPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	priv, _, err := pem2RsaPrivateKeyWithType(der)
 	if err != nil {
-		return nil, errors.New(CurrentFunctionName() + ":failed to parse PEM block:" + err.Error())
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
 	}
-	return pub, nil
+	return priv, nil
+}
+
+// ParsePrivateKeyAuto parses an RSA private key which is either PEM-encoded, see Pem2RsaPrivateKey, or
+// raw DER in PKCS#1 or PKCS#8 format. The format is detected automatically.
+func ParsePrivateKeyAuto(data []byte) (*rsa.PrivateKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		return Pem2RsaPrivateKey(data)
+	}
+	if priv, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		return priv, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:neither PEM nor DER encoded private key", CurrentFunctionName(), ErrParse)
+	}
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s:%w:Unsupported private key type, not RSA.", CurrentFunctionName(), ErrParse)
+	}
+	return priv, nil
+}
+
+// ReadPrivateKey reads a PEM-encoded RSA private key from the reader, e.g. an embedded file, a network
+// connection, or a buffer, see Pem2RsaPrivateKey.
+func ReadPrivateKey(r io.Reader) (*rsa.PrivateKey, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":reading:" + err.Error())
+	}
+	return Pem2RsaPrivateKey(buf)
 }
 
 // LoadPrivateKey load a PEM-encoded RSA private key from a file
 func LoadPrivateKey(filename string) (*rsa.PrivateKey, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
+	}
+	defer file.Close()
+	return ReadPrivateKey(file)
+}
+
+// LoadPrivateKeyWithType works like LoadPrivateKey, but also returns the PEM block type of the key, i.e.
+// RSA PRIVATE KEY for PKCS#1 or PRIVATE KEY for PKCS#8. Tools can log and report the key format.
+func LoadPrivateKeyWithType(filename string) (*rsa.PrivateKey, string, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
+	}
+	priv, pemType, err := pem2RsaPrivateKeyWithType(buf)
+	if err != nil {
+		return nil, "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return priv, pemType, nil
+}
+
+// ParsePrivateKeyWithPassword parses a password-protected PEM-encoded RSA private key. Supported are
+// legacy PEM encryption with a DEK-Info header (PKCS#1) and encrypted PKCS#8 (ENCRYPTED PRIVATE KEY)
+// with PBES2, PBKDF2, and AES-CBC or 3DES-CBC. Unencrypted keys are parsed as by Pem2RsaPrivateKey.
+// If the decryption fails, usually because of a wrong password, the returned error wraps
+// ErrWrongPassword. Malformed or unsupported keys result in errors wrapping ErrParse.
+func ParsePrivateKeyWithPassword(der []byte, password string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(der)
+	if block == nil {
+		return nil, fmt.Errorf("%s:%w:failed to decode PEM block containing private key", CurrentFunctionName(), ErrParse)
+	}
+	pemType := pemTypePKCS8PrivateKey
+	var plain []byte
+	var err error
+	switch {
+	case block.Type == pemTypeEncryptedPKCS8PrivateKey:
+		if plain, err = decryptPKCS8(block.Bytes, []byte(password)); err != nil {
+			if errors.Is(err, ErrWrongPassword) {
+				return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), ErrWrongPassword)
+			}
+			return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+		}
+	case x509.IsEncryptedPEMBlock(block): // legacy PEM encryption is insecure, but still used by other systems
+		if plain, err = x509.DecryptPEMBlock(block, []byte(password)); err != nil {
+			if errors.Is(err, x509.IncorrectPasswordError) {
+				return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), ErrWrongPassword)
+			}
+			return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+		}
+		pemType = block.Type
+	default:
+		return Pem2RsaPrivateKey(der)
+	}
+	if !isDERSequence(plain) { // the padding check of the decryption alone is not reliable
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), ErrWrongPassword)
+	}
+	priv, _, err := parseRsaPrivateKeyBlock(pemType, plain)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	return priv, nil
+}
+
+// LoadPrivateKeyWithPassword loads a password-protected PEM-encoded RSA private key from a file, see
+// ParsePrivateKeyWithPassword.
+func LoadPrivateKeyWithPassword(filename, password string) (*rsa.PrivateKey, error) {
 	buf, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
 	}
-	return Pem2RsaPrivateKey(buf)
+	priv, err := ParsePrivateKeyWithPassword(buf, password)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return priv, nil
 }
 
 // Pem2RsaPublicKey load a PEM-encoded RSA public key from a buffer. The function does not try
-// to read multiple keys from the byte array. Only the first PEM block is processed.
+// to read multiple keys from the byte array. Only the first PEM block is processed, see ParsePublicKeys
+// for bundles. PUBLIC KEY blocks are parsed as PKIX, as written by WriteRsaPublicKey, and RSA PUBLIC
+// KEY blocks as PKCS#1. If PKIX parsing fails, PKCS#1 is tried as a fallback for mislabelled blocks.
 func Pem2RsaPublicKey(der []byte) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode(der)
-	if block == nil || block.Type != "PUBLIC KEY" {
-		return nil, errors.New(CurrentFunctionName() + ":failed to decode PEM block containing public key")
+	if block == nil || (block.Type != pemTypePKIXPublicKey && block.Type != pemTypePKCS1PublicKey) {
+		return nil, fmt.Errorf("%s:%w:failed to decode PEM block containing public key", CurrentFunctionName(), ErrParse)
+	}
+	pub, err := parseRsaPublicKeyBlock(block)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
 	}
+	return pub, nil
+}
+
+// parseRsaPublicKeyBlock parses a PUBLIC KEY or RSA PUBLIC KEY block, see Pem2RsaPublicKey.
+func parseRsaPublicKeyBlock(block *pem.Block) (*rsa.PublicKey, error) {
 	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return nil, errors.New(CurrentFunctionName() + ":failed to parse PEM block:" + err.Error())
+		pkcs1Pub, pkcs1Err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if pkcs1Err != nil {
+			return nil, errors.New("failed to parse PEM block:" + err.Error())
+		}
+		return pkcs1Pub, nil
 	}
 	switch pub.(type) {
 	case *rsa.PublicKey:
 		return pub.(*rsa.PublicKey), nil
 	default:
-		return nil, errors.New(CurrentFunctionName() + ":Unsupported public key type, not RSA.")
+		return nil, errors.New("Unsupported public key type, not RSA.")
 	}
 }
 
+// ReadPublicKey reads a PEM-encoded RSA public key from the reader, see Pem2RsaPublicKey.
+func ReadPublicKey(r io.Reader) (*rsa.PublicKey, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":reading:" + err.Error())
+	}
+	return Pem2RsaPublicKey(buf)
+}
+
 // LoadPublicKey load a PEM-encoded RSA public key from a file
 func LoadPublicKey(filename string) (*rsa.PublicKey, error) {
-	buf, err := os.ReadFile(filename)
+	file, err := os.Open(filename)
 	if err != nil {
 		return nil, errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
 	}
-	return Pem2RsaPublicKey(buf)
+	defer file.Close()
+	return ReadPublicKey(file)
 }
 
 // TODO VerifySignature
-// TODO EncryptAES256
-// TODO DecryptAES256
 
 // =======================================================================================
 // = Keypair Generation
 
-// WriteRsaPrivateKey converts the key to PEM format and writes them to a file.
-func WriteRsaPrivateKey(file *os.File, privKey *rsa.PrivateKey) error {
+// WritePrivateKeyTo converts the key to PKCS#1 PEM format and writes it to the writer.
+func WritePrivateKeyTo(w io.Writer, privKey *rsa.PrivateKey) error {
+	if privKey == nil {
+		return fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
 	var privateKey = &pem.Block{
-		Type:  "RSA PRIVATE KEY",
+		Type:  pemTypePKCS1PrivateKey,
 		Bytes: x509.MarshalPKCS1PrivateKey(privKey),
 	}
-	if err := pem.Encode(file, privateKey); err != nil {
+	if err := pem.Encode(w, privateKey); err != nil {
+		return errors.New(CurrentFunctionName() + ":pem encode:" + err.Error())
+	}
+	return nil
+}
+
+// WriteRsaPrivateKey converts the key to PEM format and writes them to a file, see WritePrivateKeyTo.
+// The permissions of the file are set to 0600.
+func WriteRsaPrivateKey(file *os.File, privKey *rsa.PrivateKey) error {
+	if err := WritePrivateKeyTo(file, privKey); err != nil {
+		return fmt.Errorf("%s:writeFile:%w", CurrentFunctionName(), err)
+	}
+	if err := os.Chmod(file.Name(), 0600); err != nil {
+		return fmt.Errorf("%s:chmod:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// WriteEncryptedPrivateKey converts the key to a password-protected PEM block and writes it to the file.
+// The key is written as encrypted PKCS#8 (ENCRYPTED PRIVATE KEY) using PBKDF2 with HMAC-SHA256 and
+// AES-256-CBC, which is also understood by openssl. Use LoadPrivateKeyWithPassword to read it. Use
+// WriteRsaPrivateKey to write an unencrypted key.
+func WriteEncryptedPrivateKey(file *os.File, privKey *rsa.PrivateKey, password string) error {
+	if privKey == nil {
+		return fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if password == "" {
+		return errors.New(CurrentFunctionName() + ":password is empty")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privKey)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	encrypted, err := encryptPKCS8(der, []byte(password))
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":encryption:" + err.Error())
+	}
+	if err := pem.Encode(file, &pem.Block{Type: pemTypeEncryptedPKCS8PrivateKey, Bytes: encrypted}); err != nil {
 		return errors.New(CurrentFunctionName() + ":pem encode+writeFile:" + err.Error())
 	}
 	if err := os.Chmod(file.Name(), 0600); err != nil {
@@ -203,29 +539,76 @@ func WriteRsaPrivateKey(file *os.File, privKey *rsa.PrivateKey) error {
 	return nil
 }
 
-// WriteRsaPublicKey converts the public key to PEM format and writes them to the file.
-func WriteRsaPublicKey(file *os.File, pubKey *rsa.PublicKey) error {
+// publicKeyPemBlock converts the public key to a PKIX-encoded PEM block.
+func publicKeyPemBlock(pubKey *rsa.PublicKey) (*pem.Block, error) {
 	asn1Bytes, err := x509.MarshalPKIXPublicKey(pubKey)
 	if err != nil {
-		return errors.New(CurrentFunctionName() + ":1:" + err.Error())
+		return nil, err
 	}
 	CondDebugln(fmt.Sprintf("Length of Public Key: %d", len(asn1Bytes)))
-	var pemkey = &pem.Block{
-		Type:  "PUBLIC KEY",
+	return &pem.Block{
+		Type:  pemTypePKIXPublicKey,
 		Bytes: asn1Bytes,
+	}, nil
+}
+
+// WritePublicKeyTo converts the public key to PKIX PEM format and writes it to the writer.
+func WritePublicKeyTo(w io.Writer, pubKey *rsa.PublicKey) error {
+	if pubKey == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	pemkey, err := publicKeyPemBlock(pubKey)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":1:" + err.Error())
 	}
-	if err := pem.Encode(file, pemkey); err != nil {
+	if err := pem.Encode(w, pemkey); err != nil {
 		return errors.New(CurrentFunctionName() + ":2:" + err.Error())
 	}
 	return nil
 }
 
+// WriteRsaPublicKey converts the public key to PEM format and writes them to the file, see
+// WritePublicKeyTo.
+func WriteRsaPublicKey(file *os.File, pubKey *rsa.PublicKey) error {
+	if err := WritePublicKeyTo(file, pubKey); err != nil {
+		return fmt.Errorf("%s:writeFile:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// WritePublicKeyWithComment writes a comment line starting with # followed by the public key in PEM
+// format. Multi-line comments result in multiple comment lines. Pem2RsaPublicKey and LoadPublicKey
+// skip such leading lines.
+func WritePublicKeyWithComment(w io.Writer, pub *rsa.PublicKey, comment string) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	pemkey, err := publicKeyPemBlock(pub)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		if _, err := io.WriteString(w, "# "+line+"\n"); err != nil {
+			return errors.New(CurrentFunctionName() + ":writing comment:" + err.Error())
+		}
+	}
+	if err := pem.Encode(w, pemkey); err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return nil
+}
+
 // createRSAKeyPair2 creates the keypair and calls the functions to write the keys to the files
 func createRSAKeyPair2(privKeyFile *os.File, pubKeyFile *os.File) error {
 	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
 	if err != nil {
 		return errors.New(CurrentFunctionName() + "key creation:" + err.Error())
 	}
+	return writeRSAKeyPair(privKeyFile, pubKeyFile, privateKey)
+}
+
+// writeRSAKeyPair writes the private and the public key to the files.
+func writeRSAKeyPair(privKeyFile *os.File, pubKeyFile *os.File, privateKey *rsa.PrivateKey) error {
 	if err := WriteRsaPrivateKey(privKeyFile, privateKey); err != nil {
 		return errors.New(CurrentFunctionName() + "private key writing:" + err.Error())
 	}
@@ -235,40 +618,283 @@ func createRSAKeyPair2(privKeyFile *os.File, pubKeyFile *os.File) error {
 	return nil
 }
 
-// CreateRSAKeyPair2File checks if the 2 required files do not exist and can be created sucessfully. Then,
-// it transfers control to createKeyPairError2.
-func CreateRSAKeyPair2File(outfileName string) error {
-	var privKeyFile *os.File
-	var pubKeyFile *os.File
-	var err error
+// createKeyPairFiles checks if the private key file and the public key file do not exist yet and creates
+// both of them. If the public key file cannot be created, the private key file is removed again.
+func createKeyPairFiles(privName, pubName string) (privKeyFile *os.File, pubKeyFile *os.File, err error) {
+	if filepath.Clean(privName) == filepath.Clean(pubName) {
+		return nil, nil, errors.New("Public key file " + pubName + " is the private key file.")
+	}
+	if _, err = os.Stat(privName); err == nil {
+		return nil, nil, errors.New("Private key file " + privName + " already exists.")
+	}
+	if _, err = os.Stat(pubName); err == nil {
+		return nil, nil, errors.New("Public key file " + pubName + " already exists.")
+	}
+	if privKeyFile, err = os.Create(privName); err != nil {
+		return nil, nil, errors.New("Error creating private key file " + privName + ":" + err.Error())
+	}
+	if pubKeyFile, err = os.Create(pubName); err != nil {
+		privKeyFile.Close()
+		_ = os.Remove(privName)
+		return nil, nil, errors.New("Error creating public key file " + pubName + ":" + err.Error())
+	}
+	return privKeyFile, pubKeyFile, nil
+}
 
-	const publicKeyFileSuffix = ".pub"
+// createRSAKeyPairFiles creates the key files, see createKeyPairFiles, and writes a new key pair to them.
+// If the key pair cannot be written completely, both files are removed. If overwrite is true, existing
+// files are replaced by replaceRSAKeyPairFiles instead.
+func createRSAKeyPairFiles(privName, pubName string, overwrite bool) error {
+	if overwrite {
+		privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+		if err != nil {
+			return errors.New(CurrentFunctionName() + "key creation:" + err.Error())
+		}
+		return replaceRSAKeyPairFiles(privName, pubName, privateKey)
+	}
+	privKeyFile, pubKeyFile, err := createKeyPairFiles(privName, pubName)
+	if err != nil {
+		return err
+	}
+	err = createRSAKeyPair2(privKeyFile, pubKeyFile)
+	privKeyFile.Close()
+	pubKeyFile.Close()
+	if err != nil {
+		_ = os.Remove(privName)
+		_ = os.Remove(pubName)
+		return err
+	}
+	return nil
+}
 
-	if _, err = os.Stat(outfileName); err == nil {
-		return errors.New("Private key file " + outfileName + " already exists.")
+// replaceRSAKeyPairFiles writes the key pair to temporary files next to the key files, syncs them, and
+// renames them over the existing files. The public key is renamed first, so that the old private key is
+// only replaced once the new public key is in place. On errors, only the temporary files are removed and
+// the existing files are kept.
+func replaceRSAKeyPairFiles(privName, pubName string, privateKey *rsa.PrivateKey) error {
+	if filepath.Clean(privName) == filepath.Clean(pubName) {
+		return errors.New("Public key file " + pubName + " is the private key file.")
+	}
+	privTmp, err := os.CreateTemp(filepath.Dir(privName), filepath.Base(privName)+".tmp*")
+	if err != nil {
+		return errors.New("Error creating temporary private key file for " + privName + ":" + err.Error())
+	}
+	defer func() {
+		privTmp.Close()
+		_ = os.Remove(privTmp.Name()) // fails harmlessly after the renaming
+	}()
+	pubTmp, err := os.CreateTemp(filepath.Dir(pubName), filepath.Base(pubName)+".tmp*")
+	if err != nil {
+		return errors.New("Error creating temporary public key file for " + pubName + ":" + err.Error())
 	}
-	if _, err = os.Stat(outfileName + publicKeyFileSuffix); err == nil {
-		return errors.New("Public key file " + outfileName + " already exists.")
+	defer func() {
+		pubTmp.Close()
+		_ = os.Remove(pubTmp.Name())
+	}()
+	if err := writeRSAKeyPair(privTmp, pubTmp, privateKey); err != nil {
+		return err
 	}
-	if privKeyFile, err = os.Create(outfileName); err != nil {
-		return errors.New("Error creating private key file " + outfileName + ":" + err.Error())
+	if err := os.Chmod(pubTmp.Name(), 0644); err != nil { // CreateTemp uses 0600
+		return errors.New("Error setting permissions of public key file " + pubName + ":" + err.Error())
 	}
-	if pubKeyFile, err = os.Create(outfileName + publicKeyFileSuffix); err != nil {
-		return errors.New("Error creating private key file " + outfileName + ":" + err.Error())
+	for _, file := range []*os.File{privTmp, pubTmp} {
+		if err := file.Sync(); err != nil {
+			return errors.New("Error syncing " + file.Name() + ":" + err.Error())
+		}
+		if err := file.Close(); err != nil {
+			return errors.New("Error closing " + file.Name() + ":" + err.Error())
+		}
 	}
-	defer privKeyFile.Close()
-	defer pubKeyFile.Close()
-	return createRSAKeyPair2(privKeyFile, pubKeyFile)
+	if err := os.Rename(pubTmp.Name(), pubName); err != nil {
+		return errors.New("Error replacing public key file " + pubName + ":" + err.Error())
+	}
+	if err := os.Rename(privTmp.Name(), privName); err != nil {
+		return errors.New("Error replacing private key file " + privName + ":" + err.Error())
+	}
+	return nil
+}
+
+// CreateRSAKeyPair2File checks if the 2 required files do not exist and can be created sucessfully. Then,
+// it transfers control to createKeyPairError2.
+func CreateRSAKeyPair2File(outfileName string) error {
+	return createRSAKeyPairFiles(outfileName, outfileName+publicKeyFileSuffix, false)
+}
+
+// CreateRSAKeyPair2FileForce works like CreateRSAKeyPair2File, but if overwrite is true, existing key
+// files are replaced instead of returning an error, e.g. for re-provisioning scripts. The new keys are
+// written to temporary files first and then renamed over the existing files, so that a failed
+// re-provisioning keeps the old key pair.
+func CreateRSAKeyPair2FileForce(outfileName string, overwrite bool) error {
+	return createRSAKeyPairFiles(outfileName, outfileName+publicKeyFileSuffix, overwrite)
+}
+
+// CreateRSAKeyPair2FileSuffix works like CreateRSAKeyPair2File, but the public key is written to
+// privName + pubSuffix, e.g. _pub.pem or .pem.pub, instead of privName.pub. An error is returned if the
+// name of the public key file is the name of the private key file, e.g. for an empty suffix.
+func CreateRSAKeyPair2FileSuffix(privName, pubSuffix string) error {
+	if err := createRSAKeyPairFiles(privName, privName+pubSuffix, false); err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return nil
 }
 
 // CreateRSAKeyPair creates an RSA 4096-bit key-pair. This function makes only partly sense,
 // as the private key always contains the public key.
 func CreateRSAKeyPair() (*rsa.PrivateKey, *rsa.PublicKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+	return CreateRSAKeyPairBits(bitSize)
+}
+
+// CreateRSAKeyPairBits creates an RSA key-pair of the given size. Supported are 2048, 3072, and 4096
+// bits. Smaller keys are not secure anymore. 2048 bits can be used where the generation of 4096-bit
+// keys is too slow, e.g. in CI or on embedded targets.
+func CreateRSAKeyPairBits(bits int) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	if err := checkRSAKeyPairBits(bits); err != nil {
+		return nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
 	if err != nil {
 		return nil, nil, errors.New(CurrentFunctionName() + "key creation:" + err.Error())
 	}
 	return privateKey, &privateKey.PublicKey, nil
 }
 
+// checkRSAKeyPairBits returns an error if bits is not a supported key size of CreateRSAKeyPairBits.
+func checkRSAKeyPairBits(bits int) error {
+	switch {
+	case bits < 2048:
+		return errors.New("key size " + strconv.Itoa(bits) + " too small, minimum is 2048")
+	case bits != 2048 && bits != 3072 && bits != 4096:
+		return errors.New("unsupported key size " + strconv.Itoa(bits) + ", use 2048, 3072, or 4096")
+	}
+	return nil
+}
+
+// CreateRSAKeyPairContext works like CreateRSAKeyPairBits, but returns ctx.Err() as soon as the context is
+// cancelled or times out, e.g. to bound the generation in a request handler or during a graceful
+// shutdown. rsa.GenerateKey itself cannot be interrupted, so an abandoned generation finishes in the
+// background and its result is discarded.
+func CreateRSAKeyPairContext(ctx context.Context, bits int) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	if err := checkRSAKeyPairBits(bits); err != nil {
+		return nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	type result struct {
+		key *rsa.PrivateKey
+		err error
+	}
+	done := make(chan result, 1) // buffered, so the goroutine never blocks if nobody receives anymore
+	go func() {
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		done <- result{key, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, nil, fmt.Errorf("%s:%w", CurrentFunctionName(), ctx.Err())
+	case res := <-done:
+		if res.err != nil {
+			return nil, nil, errors.New(CurrentFunctionName() + ":key creation:" + res.err.Error())
+		}
+		return res.key, &res.key.PublicKey, nil
+	}
+}
+
+// CreateRSAKeyPairPrecomputed works like CreateRSAKeyPair, but makes explicit that the CRT values of the
+// private key are precomputed, which speeds up every signing and decryption. rsa.GenerateKey already
+// precomputes them, so the additional Precompute call is a no-op and costs nothing. Keys loaded by
+// LoadPrivateKey and Pem2RsaPrivateKey are precomputed by the x509 parsers as well. Only keys assembled
+// from their fields lack the CRT values until Precompute is called.
+func CreateRSAKeyPairPrecomputed() (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	privateKey, publicKey, err := CreateRSAKeyPair()
+	if err != nil {
+		return nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	privateKey.Precompute()
+	return privateKey, publicKey, nil
+}
+
+// PrivateKeyToPEMString returns the private key as PKCS#1 PEM text, e.g. for config files, environment
+// variables, or Kubernetes secrets. The text is identical to the output of WriteRsaPrivateKey.
+func PrivateKeyToPEMString(priv *rsa.PrivateKey) (string, error) {
+	var sb strings.Builder
+	if err := WritePrivateKeyTo(&sb, priv); err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return sb.String(), nil
+}
+
+// PublicKeyToPEMString returns the public key as PKIX PEM text. The text is identical to the output of
+// WriteRsaPublicKey.
+func PublicKeyToPEMString(pub *rsa.PublicKey) (string, error) {
+	var sb strings.Builder
+	if err := WritePublicKeyTo(&sb, pub); err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return sb.String(), nil
+}
+
+// PrivateKeyFromPEMString parses a PEM-encoded RSA private key from a string, see Pem2RsaPrivateKey.
+func PrivateKeyFromPEMString(s string) (*rsa.PrivateKey, error) {
+	return Pem2RsaPrivateKey([]byte(s))
+}
+
+// PublicKeyFromPEMString parses a PEM-encoded RSA public key from a string, see Pem2RsaPublicKey.
+func PublicKeyFromPEMString(s string) (*rsa.PublicKey, error) {
+	return Pem2RsaPublicKey([]byte(s))
+}
+
+// CreateRSAKeyPairPEMStrings creates an RSA key-pair of the given size, see CreateRSAKeyPairBits, and returns
+// the PKCS#1 PEM of the private key and the PKIX PEM of the public key without using the filesystem,
+// e.g. to display them in a UI.
+func CreateRSAKeyPairPEMStrings(bits int) (privPEM, pubPEM string, err error) {
+	if err := checkRSAKeyPairBits(bits); err != nil {
+		return "", "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return "", "", errors.New(CurrentFunctionName() + ":key creation:" + err.Error())
+	}
+	if privPEM, err = PrivateKeyToPEMString(privateKey); err != nil {
+		return "", "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if pubPEM, err = PublicKeyToPEMString(&privateKey.PublicKey); err != nil {
+		return "", "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return privPEM, pubPEM, nil
+}
+
+// randomBytes returns n bytes read from crypto/rand. There is no fallback to math/rand.
+func randomBytes(n int) ([]byte, error) {
+	if n <= 0 {
+		return nil, errors.New("number of bytes " + strconv.Itoa(n) + " must be positive")
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// RandomToken returns nBytes random bytes from crypto/rand, the source of the key generation, as
+// unpadded base64url, e.g. for API tokens or nonces which are used in URLs. 32 bytes are recommended
+// for tokens.
+func RandomToken(nBytes int) (string, error) {
+	buf, err := randomBytes(nBytes)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// RandomHex works like RandomToken, but returns the random bytes as lowercase hex string of 2*nBytes
+// characters.
+func RandomHex(nBytes int) (string, error) {
+	buf, err := randomBytes(nBytes)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return hex.EncodeToString(buf), nil
+}
+
 // EOF
@@ -0,0 +1,47 @@
+package go_libs
+
+import (
+	runtimedebug "runtime/debug"
+	"strconv"
+)
+
+// modulePath is the module path of this package, used to find its version in the build info.
+const modulePath = "github.com/engelch/go_libs/v2"
+
+// moduleVersion returns the version of this module as recorded in the build info of the binary, or
+// unknown if it is not available, e.g. in tests.
+func moduleVersion() string {
+	info, ok := runtimedebug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// CryptoInfo returns the version of this package and the effective crypto policy: the default RSA key
+// size, hash, PSS salt length, and base64 encoding, and the state of the toggles AllowSHA1 and
+// FingerprintInErrors. Ops can log it at startup to confirm the policy.
+func CryptoInfo() map[string]string {
+	return map[string]string{
+		"version":               moduleVersion(),
+		"rsa_bits":              strconv.Itoa(bitSize),
+		"hash":                  "SHA-256",
+		"pss_salt_length":       "auto",
+		"base64":                "standard",
+		"sha1_allowed":          strconv.FormatBool(globalAllowSHA1.Load().(bool)),
+		"fingerprint_in_errors": strconv.FormatBool(globalFingerprintInErrors.Load().(bool)),
+	}
+}
+
+// EOF
@@ -0,0 +1,31 @@
+package go_libs
+
+import (
+	"testing"
+)
+
+func TestCryptoInfo(t *testing.T) {
+	info := CryptoInfo()
+	for key, expected := range map[string]string{
+		"rsa_bits":              "4096",
+		"hash":                  "SHA-256",
+		"pss_salt_length":       "auto",
+		"base64":                "standard",
+		"sha1_allowed":          "false",
+		"fingerprint_in_errors": "false",
+	} {
+		if info[key] != expected {
+			t.Errorf("CryptoInfo[%s] error, is:%s, expected:%s\n", key, info[key], expected)
+		}
+	}
+	if info["version"] == "" {
+		t.Errorf("CryptoInfo has no version\n")
+	}
+	AllowSHA1(true)
+	defer AllowSHA1(false)
+	if info := CryptoInfo(); info["sha1_allowed"] != "true" {
+		t.Errorf("CryptoInfo[sha1_allowed] error, is:%s, expected:true\n", info["sha1_allowed"])
+	}
+}
+
+// EOF
@@ -0,0 +1,39 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// createCSR creates a PEM encoded certificate signing request for the subject, signed by key with
+// the signature algorithm.
+func createCSR(key *rsa.PrivateKey, subject pkix.Name, sigAlg x509.SignatureAlgorithm) ([]byte, error) {
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            subject,
+		SignatureAlgorithm: sigAlg,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// CreateCSRPSS creates a PEM encoded certificate signing request for the subject which is signed with
+// RSA-PSS and SHA-256, as required by some CAs.
+func CreateCSRPSS(key *rsa.PrivateKey, subject pkix.Name) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	csr, err := createCSR(key, subject, x509.SHA256WithRSAPSS)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return csr, nil
+}
+
+// EOF
@@ -0,0 +1,35 @@
+package go_libs
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+)
+
+func TestCreateCSRPSS(t *testing.T) {
+	key := testPrivateKey(t)
+	csrPEM, err := CreateCSRPSS(key, pkix.Name{CommonName: "service.example.com", Organization: []string{"Example"}})
+	if err != nil {
+		t.Fatalf("CreateCSRPSS failed:%s\n", err)
+	}
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		t.Fatalf("CSR is not a PEM encoded certificate request\n")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificateRequest failed:%s\n", err)
+	}
+	if csr.SignatureAlgorithm != x509.SHA256WithRSAPSS {
+		t.Errorf("Signature algorithm error, is:%s, expected:%s\n", csr.SignatureAlgorithm, x509.SHA256WithRSAPSS)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		t.Errorf("CheckSignature failed:%s\n", err)
+	}
+	if csr.Subject.CommonName != "service.example.com" {
+		t.Errorf("Subject error, is:%s, expected:%s\n", csr.Subject.CommonName, "service.example.com")
+	}
+}
+
+// EOF
@@ -0,0 +1,56 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// joinDelimited joins the parts with the separator. It returns an error if a part contains the
+// separator, as the boundaries between the parts would become ambiguous.
+func joinDelimited(parts []string, sep byte) ([]byte, error) {
+	for i, part := range parts {
+		if strings.IndexByte(part, sep) >= 0 {
+			return nil, errors.New("part " + strconv.Itoa(i) + " contains the separator " + strconv.QuoteRune(rune(sep)))
+		}
+	}
+	return []byte(strings.Join(parts, string([]byte{sep}))), nil
+}
+
+// SignDelimited signs multiple strings as one message with RSA-PSS. The parts are joined with the
+// separator sep, which must not appear in any part. So, ["ab", "c"] and ["a", "bc"] always result in
+// different messages. Use VerifyDelimited for verification.
+func SignDelimited(key *rsa.PrivateKey, parts []string, sep byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	msg, err := joinDelimited(parts, sep)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	sig, err := SignPSSByteArray(key, Sha256bytes2bytes(msg))
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return sig, nil
+}
+
+// VerifyDelimited verifies a signature created by SignDelimited for the parts and the separator. If
+// no error is returned, the verification was successful.
+func VerifyDelimited(pub *rsa.PublicKey, parts []string, sep byte, sig []byte) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	msg, err := joinDelimited(parts, sep)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := VerifyPSSByteArray(pub, sig, msg); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// EOF
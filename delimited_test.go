@@ -0,0 +1,27 @@
+package go_libs
+
+import (
+	"testing"
+)
+
+func TestSignDelimited(t *testing.T) {
+	key := testPrivateKey(t)
+	sig, err := SignDelimited(key, []string{"ab", "c"}, '|')
+	if err != nil {
+		t.Fatalf("SignDelimited failed:%s\n", err)
+	}
+	if err := VerifyDelimited(&key.PublicKey, []string{"ab", "c"}, '|', sig); err != nil {
+		t.Errorf("VerifyDelimited failed:%s\n", err)
+	}
+	if err := VerifyDelimited(&key.PublicKey, []string{"a", "bc"}, '|', sig); err == nil {
+		t.Errorf("Moved boundary should fail verification\n")
+	}
+	if _, err := SignDelimited(key, []string{"a|b", "c"}, '|'); err == nil {
+		t.Errorf("Part containing the separator should be rejected\n")
+	}
+	if err := VerifyDelimited(&key.PublicKey, []string{"ab|c"}, '|', sig); err == nil {
+		t.Errorf("Verification of a part containing the separator should fail\n")
+	}
+}
+
+// EOF
@@ -0,0 +1,90 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// seededReader is a deterministic byte stream: SHA-256(seed || counter) for counter = 0, 1, ...
+// It is NOT a secure random number generator.
+type seededReader struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// Read fills p with the next bytes of the stream. It never fails.
+func (r *seededReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		if len(r.buf) == 0 {
+			var ctr [8]byte
+			binary.BigEndian.PutUint64(ctr[:], r.counter)
+			r.counter++
+			block := sha256.Sum256(append(append([]byte{}, r.seed...), ctr[:]...))
+			r.buf = block[:]
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return len(p), nil
+}
+
+// deterministicPrime returns the first prime of the given bit length found from the stream, where
+// p-1 is coprime to e. The top 2 bits are set, so that the product of 2 such primes has 2*bits bits.
+func deterministicPrime(r *seededReader, bits int, e *big.Int) *big.Int {
+	buf := make([]byte, (bits+7)/8)
+	one := big.NewInt(1)
+	for {
+		_, _ = r.Read(buf)
+		if excess := len(buf)*8 - bits; excess > 0 {
+			buf[0] &= byte(0xff >> excess)
+		}
+		p := new(big.Int).SetBytes(buf)
+		p.SetBit(p, bits-1, 1)
+		p.SetBit(p, bits-2, 1)
+		p.SetBit(p, 0, 1)
+		if !p.ProbablyPrime(20) {
+			continue
+		}
+		if new(big.Int).GCD(nil, nil, new(big.Int).Sub(p, one), e).Cmp(one) == 0 {
+			return p
+		}
+	}
+}
+
+// DeterministicKeyPair creates an RSA key from the seed. The same seed and size always result in the
+// same key, which gives stable fixtures for reproducible integration tests.
+//
+// FOR TESTS ONLY. The key is as secret as the seed and the generation is not hardened. Never use such
+// keys in production, use CreateRSAKeyPair instead.
+func DeterministicKeyPair(seed []byte, bits int) (*rsa.PrivateKey, error) {
+	if bits < 1024 || bits%2 != 0 {
+		return nil, errors.New(CurrentFunctionName() + ":unsupported key size " + strconv.Itoa(bits))
+	}
+	r := &seededReader{seed: seed}
+	e := big.NewInt(65537)
+	p := deterministicPrime(r, bits/2, e)
+	q := deterministicPrime(r, bits/2, e)
+	for p.Cmp(q) == 0 {
+		q = deterministicPrime(r, bits/2, e)
+	}
+	one := big.NewInt(1)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+	key := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: new(big.Int).Mul(p, q), E: int(e.Int64())},
+		D:         new(big.Int).ModInverse(e, phi),
+		Primes:    []*big.Int{p, q},
+	}
+	if err := key.Validate(); err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	key.Precompute()
+	return key, nil
+}
+
+// EOF
@@ -0,0 +1,39 @@
+package go_libs
+
+import (
+	"testing"
+)
+
+func TestDeterministicKeyPair(t *testing.T) {
+	key1, err := DeterministicKeyPair([]byte("fixture seed"), 1024)
+	if err != nil {
+		t.Fatalf("DeterministicKeyPair failed:%s\n", err)
+	}
+	key2, err := DeterministicKeyPair([]byte("fixture seed"), 1024)
+	if err != nil {
+		t.Fatalf("DeterministicKeyPair failed:%s\n", err)
+	}
+	other, err := DeterministicKeyPair([]byte("other seed"), 1024)
+	if err != nil {
+		t.Fatalf("DeterministicKeyPair failed:%s\n", err)
+	}
+	if key1.N.Cmp(key2.N) != 0 {
+		t.Errorf("Same seed resulted in different moduli\n")
+	}
+	if key1.N.Cmp(other.N) == 0 {
+		t.Errorf("Different seeds resulted in the same modulus\n")
+	}
+	if key1.N.BitLen() != 1024 {
+		t.Errorf("Key size error, is:%d, expected:1024\n", key1.N.BitLen())
+	}
+	msg := []byte("deterministic")
+	sig, err := SignPSSByteArray(key1, Sha256bytes2bytes(msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArray failed:%s\n", err)
+	}
+	if err := VerifyPSSByteArray(&key2.PublicKey, sig, msg); err != nil {
+		t.Errorf("Verification with the key from the same seed failed:%s\n", err)
+	}
+}
+
+// EOF
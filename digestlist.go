@@ -0,0 +1,68 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// sortedDigests returns the concatenation of the sorted digests. Sorting makes the result independent
+// of the order of the list. The list itself is not modified.
+func sortedDigests(digests [][]byte) []byte {
+	sorted := make([][]byte, len(digests))
+	copy(sorted, digests)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	return bytes.Join(sorted, nil)
+}
+
+// checkDigestLengths requires all digests to be non-empty and of the same length. Otherwise, the
+// concatenation of the sorted digests would be ambiguous, e.g. {ab, c} and {a, bc}.
+func checkDigestLengths(digests [][]byte) error {
+	for _, d := range digests {
+		if len(d) == 0 || len(d) != len(digests[0]) {
+			return fmt.Errorf("%w:digests must be non-empty and of equal length", ErrParse)
+		}
+	}
+	return nil
+}
+
+// SignDigestList signs a whole list of digests (of equal length, e.g. SHA-256) with a single RSA-PSS
+// signature. This amortises the signing cost for batches, see VerifyDigestInList.
+func SignDigestList(key *rsa.PrivateKey, digests [][]byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if len(digests) == 0 {
+		return nil, errors.New(CurrentFunctionName() + ":digest list is empty")
+	}
+	if err := checkDigestLengths(digests); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return SignPSSByteArray(key, Sha256bytes2bytes(sortedDigests(digests)))
+}
+
+// VerifyDigestInList verifies that the digest is part of the list and that sig is a signature of the
+// whole list created by SignDigestList. If no error is returned, the digest belongs to the signed set.
+func VerifyDigestInList(pub *rsa.PublicKey, digest []byte, allDigests [][]byte, sig []byte) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if err := checkDigestLengths(allDigests); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	found := false
+	for _, d := range allDigests {
+		if SecureCompare(d, digest) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.New(CurrentFunctionName() + ":digest is not part of the list")
+	}
+	return VerifyPSSByteArray(pub, sig, sortedDigests(allDigests))
+}
+
+// EOF
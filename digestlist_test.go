@@ -0,0 +1,46 @@
+package go_libs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyDigestInList(t *testing.T) {
+	key := testPrivateKey(t)
+	var digests [][]byte
+	for _, msg := range []string{"first", "second", "third"} {
+		digests = append(digests, Sha256bytes2bytes([]byte(msg)))
+	}
+	sig, err := SignDigestList(key, digests)
+	if err != nil {
+		t.Fatalf("SignDigestList failed:%s\n", err)
+	}
+	reordered := [][]byte{digests[2], digests[0], digests[1]}
+	if err := VerifyDigestInList(&key.PublicKey, digests[1], reordered, sig); err != nil {
+		t.Errorf("Membership verification failed:%s\n", err)
+	}
+	if err := VerifyDigestInList(&key.PublicKey, Sha256bytes2bytes([]byte("fourth")), digests, sig); err == nil {
+		t.Errorf("Digest not in the list should be rejected\n")
+	}
+	forged := append(digests[:2:2], Sha256bytes2bytes([]byte("fourth")))
+	if err := VerifyDigestInList(&key.PublicKey, forged[2], forged, sig); err == nil {
+		t.Errorf("Modified list should be rejected\n")
+	}
+}
+
+func TestDigestListUnequalLengths(t *testing.T) {
+	key := testPrivateKey(t)
+	if _, err := SignDigestList(key, [][]byte{[]byte("ab"), []byte("c")}); !errors.Is(err, ErrParse) {
+		t.Errorf("Unequal lengths error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	sig, err := SignDigestList(key, [][]byte{[]byte("ab"), []byte("cd")})
+	if err != nil {
+		t.Fatalf("SignDigestList failed:%s\n", err)
+	}
+	// same concatenation abcd, but different digests
+	if err := VerifyDigestInList(&key.PublicKey, []byte("a"), [][]byte{[]byte("a"), []byte("bcd")}, sig); !errors.Is(err, ErrParse) {
+		t.Errorf("Ambiguous list error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+// EOF
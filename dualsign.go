@@ -0,0 +1,35 @@
+package go_libs
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// DualSign signs the message with an RSA key (RSA-PSS over the SHA-256 digest) and with an Ed25519 key
+// (over the message itself). It supports the migration from RSA to Ed25519, see VerifyDual.
+func DualSign(rsaKey *rsa.PrivateKey, edKey ed25519.PrivateKey, msg []byte) (rsaSig, edSig []byte, err error) {
+	if rsaKey == nil || len(edKey) != ed25519.PrivateKeySize {
+		return nil, nil, fmt.Errorf("%s:Error, %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if rsaSig, err = SignPSSByteArray(rsaKey, Sha256bytes2bytes(msg)); err != nil {
+		return nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return rsaSig, ed25519.Sign(edKey, msg), nil
+}
+
+// VerifyDual verifies signatures created by DualSign. It succeeds if either the RSA or the Ed25519
+// signature verifies, so verifiers can trust either algorithm during a transition period. A key
+// which is not (yet) known to the verifier can be passed as nil.
+func VerifyDual(rsaPub *rsa.PublicKey, edPub ed25519.PublicKey, msg []byte, rsaSig, edSig []byte) error {
+	if len(edPub) == ed25519.PublicKeySize && ed25519.Verify(edPub, msg, edSig) {
+		return nil
+	}
+	if rsaPub != nil && VerifyPSSByteArray(rsaPub, rsaSig, msg) == nil {
+		return nil
+	}
+	return fmt.Errorf("%s:%w:neither RSA nor Ed25519 signature is valid", CurrentFunctionName(), ErrVerification)
+}
+
+// EOF
@@ -0,0 +1,33 @@
+package go_libs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+func TestVerifyDualOnlyEd25519Valid(t *testing.T) {
+	rsaKey := testPrivateKey(t)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey failed:%s\n", err)
+	}
+	msg := []byte("migrating message")
+	rsaSig, edSig, err := DualSign(rsaKey, edKey, msg)
+	if err != nil {
+		t.Fatalf("DualSign failed:%s\n", err)
+	}
+	if err := VerifyDual(&rsaKey.PublicKey, edPub, msg, rsaSig, edSig); err != nil {
+		t.Errorf("VerifyDual failed for valid signatures:%s\n", err)
+	}
+	rsaSig[0] ^= 0xff
+	if err := VerifyDual(&rsaKey.PublicKey, edPub, msg, rsaSig, edSig); err != nil {
+		t.Errorf("VerifyDual should accept the valid Ed25519 signature:%s\n", err)
+	}
+	edSig[0] ^= 0xff
+	if err := VerifyDual(&rsaKey.PublicKey, edPub, msg, rsaSig, edSig); err == nil {
+		t.Errorf("VerifyDual should fail if both signatures are invalid\n")
+	}
+}
+
+// EOF
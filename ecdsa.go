@@ -0,0 +1,40 @@
+package go_libs
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// SignByteArrayECDSA returns the ASN.1-encoded ECDSA signature of the digest, e.g. with a P-256 key.
+// It is the ECDSA counterpart of SignPSSByteArray, so the digest is the SHA-256 digest of the message,
+// see Sha256bytes2bytes.
+func SignByteArrayECDSA(key *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return signature, nil
+}
+
+// VerifyByteArrayECDSA verifies an ASN.1-encoded ECDSA signature (digest) as created by
+// SignByteArrayECDSA. Like VerifyPSSByteArray, it recalculates the SHA-256 digest of the message. If no
+// error is returned, then the verification was successful.
+func VerifyByteArrayECDSA(key *ecdsa.PublicKey, digest []byte, msg string) error {
+	if key == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if digest == nil {
+		return fmt.Errorf("%s:%w:Error, digest is nil", CurrentFunctionName(), ErrParse)
+	}
+	if !ecdsa.VerifyASN1(key, Sha256bytes2bytes([]byte(msg)), digest) {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), ErrVerification)
+	}
+	return nil
+}
+
+// EOF
@@ -0,0 +1,32 @@
+package go_libs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestECDSASignVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed:%s\n", err)
+	}
+	const msg = "signed with P-256"
+	sig, err := SignByteArrayECDSA(key, Sha256bytes2bytes([]byte(msg)))
+	if err != nil {
+		t.Fatalf("SignByteArrayECDSA failed:%s\n", err)
+	}
+	if err := VerifyByteArrayECDSA(&key.PublicKey, sig, msg); err != nil {
+		t.Errorf("VerifyByteArrayECDSA failed:%s\n", err)
+	}
+	if err := VerifyByteArrayECDSA(&key.PublicKey, sig, msg+"!"); !errors.Is(err, ErrVerification) {
+		t.Errorf("Modified message error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	if _, err := SignByteArrayECDSA(nil, Sha256bytes2bytes([]byte(msg))); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+// EOF
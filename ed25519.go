@@ -0,0 +1,124 @@
+package go_libs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CreateEd25519KeyPair creates an Ed25519 key-pair. Ed25519 keys and signatures are small and fast,
+// e.g. for tokens between services.
+func CreateEd25519KeyPair() (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, errors.New(CurrentFunctionName() + ":key creation:" + err.Error())
+	}
+	return priv, pub, nil
+}
+
+// SignEd25519 returns the Ed25519 signature of the message. Unlike the RSA functions like
+// SignPSSByteArray, Ed25519 signs the message itself and not a pre-computed digest; the hashing is
+// part of the algorithm. Like ed25519.Sign, it panics if the key is not ed25519.PrivateKeySize bytes long,
+// e.g. nil. Keys from CreateEd25519KeyPair and Pem2Ed25519PrivateKey always have the correct size.
+func SignEd25519(key ed25519.PrivateKey, msg []byte) []byte {
+	return ed25519.Sign(key, msg)
+}
+
+// VerifyEd25519 verifies the Ed25519 signature of the message, see SignEd25519. If no error is
+// returned, then the verification was successful.
+func VerifyEd25519(key ed25519.PublicKey, msg, sig []byte) error {
+	if len(key) == 0 {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%s:%w:invalid public key size", CurrentFunctionName(), ErrParse)
+	}
+	if !ed25519.Verify(key, msg, sig) {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), ErrVerification)
+	}
+	return nil
+}
+
+// WriteEd25519PrivateKey writes the key as PKCS#8 PEM block (PRIVATE KEY) to the file, as openssl does.
+func WriteEd25519PrivateKey(file *os.File, privKey ed25519.PrivateKey) error {
+	if err := checkEd25519PrivateKey(privKey); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privKey)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := pem.Encode(file, &pem.Block{Type: pemTypePKCS8PrivateKey, Bytes: der}); err != nil {
+		return errors.New(CurrentFunctionName() + ":pem encode+writeFile:" + err.Error())
+	}
+	if err := os.Chmod(file.Name(), 0600); err != nil {
+		return errors.New(CurrentFunctionName() + ":chmod:" + err.Error())
+	}
+	return nil
+}
+
+// checkEd25519PrivateKey rejects empty keys and keys of the wrong size, for which marshalling panics.
+func checkEd25519PrivateKey(key ed25519.PrivateKey) error {
+	if len(key) == 0 {
+		return fmt.Errorf("Error, private %w", ErrNilKey)
+	}
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("%w:invalid private key size", ErrParse)
+	}
+	return nil
+}
+
+// WriteEd25519PublicKey writes the key as PKIX PEM block (PUBLIC KEY) to the file.
+func WriteEd25519PublicKey(file *os.File, pubKey ed25519.PublicKey) error {
+	der, err := x509.MarshalPKIXPublicKey(pubKey)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := pem.Encode(file, &pem.Block{Type: pemTypePKIXPublicKey, Bytes: der}); err != nil {
+		return errors.New(CurrentFunctionName() + ":pem encode+writeFile:" + err.Error())
+	}
+	return nil
+}
+
+// Pem2Ed25519PrivateKey loads a PEM-encoded (PKCS#8) Ed25519 private key from a buffer. Only the first
+// PEM block is processed.
+func Pem2Ed25519PrivateKey(der []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(der)
+	if block == nil || block.Type != pemTypePKCS8PrivateKey {
+		return nil, fmt.Errorf("%s:%w:failed to decode PEM block containing private key", CurrentFunctionName(), ErrParse)
+	}
+	defer zeroBytes(block.Bytes)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:failed to parse PEM block:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s:%w:Unsupported private key type, not Ed25519.", CurrentFunctionName(), ErrParse)
+	}
+	return priv, nil
+}
+
+// Pem2Ed25519PublicKey loads a PEM-encoded (PKIX) Ed25519 public key from a buffer. Only the first PEM
+// block is processed.
+func Pem2Ed25519PublicKey(der []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(der)
+	if block == nil || block.Type != pemTypePKIXPublicKey {
+		return nil, fmt.Errorf("%s:%w:failed to decode PEM block containing public key", CurrentFunctionName(), ErrParse)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:failed to parse PEM block:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s:%w:Unsupported public key type, not Ed25519.", CurrentFunctionName(), ErrParse)
+	}
+	return pub, nil
+}
+
+// EOF
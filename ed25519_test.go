@@ -0,0 +1,83 @@
+package go_libs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEd25519SignVerify(t *testing.T) {
+	priv, pub, err := CreateEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("CreateEd25519KeyPair failed:%s\n", err)
+	}
+	msg := []byte("inter-service token")
+	sig := SignEd25519(priv, msg)
+	if err := VerifyEd25519(pub, msg, sig); err != nil {
+		t.Errorf("VerifyEd25519 failed:%s\n", err)
+	}
+	if err := VerifyEd25519(pub, []byte("modified token"), sig); !errors.Is(err, ErrVerification) {
+		t.Errorf("Modified message error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+}
+
+func TestWriteEd25519PrivateKeyInvalid(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "ed25519"))
+	if err != nil {
+		t.Fatalf("Create failed:%s\n", err)
+	}
+	defer file.Close()
+	if err := WriteEd25519PrivateKey(file, nil); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Write nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+	if err := WriteEd25519PrivateKey(file, make([]byte, 10)); !errors.Is(err, ErrParse) {
+		t.Errorf("Write short key error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+func TestEd25519PEMRoundTrip(t *testing.T) {
+	priv, pub, err := CreateEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("CreateEd25519KeyPair failed:%s\n", err)
+	}
+	dir := t.TempDir()
+	privFile, err := os.Create(filepath.Join(dir, "ed25519"))
+	if err != nil {
+		t.Fatalf("Create failed:%s\n", err)
+	}
+	defer privFile.Close()
+	pubFile, err := os.Create(filepath.Join(dir, "ed25519.pub"))
+	if err != nil {
+		t.Fatalf("Create failed:%s\n", err)
+	}
+	defer pubFile.Close()
+	if err := WriteEd25519PrivateKey(privFile, priv); err != nil {
+		t.Fatalf("WriteEd25519PrivateKey failed:%s\n", err)
+	}
+	if err := WriteEd25519PublicKey(pubFile, pub); err != nil {
+		t.Fatalf("WriteEd25519PublicKey failed:%s\n", err)
+	}
+
+	buf, _ := os.ReadFile(privFile.Name())
+	loadedPriv, err := Pem2Ed25519PrivateKey(buf)
+	if err != nil {
+		t.Fatalf("Pem2Ed25519PrivateKey failed:%s\n", err)
+	}
+	if !loadedPriv.Equal(priv) {
+		t.Errorf("Private key round-trip error\n")
+	}
+	buf, _ = os.ReadFile(pubFile.Name())
+	loadedPub, err := Pem2Ed25519PublicKey(buf)
+	if err != nil {
+		t.Fatalf("Pem2Ed25519PublicKey failed:%s\n", err)
+	}
+	if !loadedPub.Equal(pub) {
+		t.Errorf("Public key round-trip error\n")
+	}
+	if _, err := Pem2Ed25519PublicKey(nil); !errors.Is(err, ErrParse) {
+		t.Errorf("Empty buffer error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+// EOF
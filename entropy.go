@@ -0,0 +1,37 @@
+package go_libs
+
+import (
+	"math"
+)
+
+// minKeyMaterialSize is the minimum length of key material accepted by LooksLikeValidKeyBytes, 128 bits.
+const minKeyMaterialSize = 16
+
+// minKeyMaterialEntropy is the minimum Shannon entropy in bits per byte accepted by LooksLikeValidKeyBytes.
+// Random bytes and their hex or base64 encodings are well above it, repeated patterns are below.
+const minKeyMaterialEntropy = 3.0
+
+// shannonEntropy returns the Shannon entropy of the byte distribution of b in bits per byte.
+func shannonEntropy(b []byte) float64 {
+	var counts [256]int
+	for _, c := range b {
+		counts[c]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(len(b))
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// LooksLikeValidKeyBytes is a sanity check before using b as key material. It rejects buffers which are
+// shorter than 16 bytes or have an obviously low entropy, e.g. all zeros or a repeated word, as caused
+// by an empty or unset environment variable. It is a heuristic and does not prove that b is random.
+func LooksLikeValidKeyBytes(b []byte) bool {
+	return len(b) >= minKeyMaterialSize && shannonEntropy(b) >= minKeyMaterialEntropy
+}
+
+// EOF
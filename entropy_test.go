@@ -0,0 +1,28 @@
+package go_libs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLooksLikeValidKeyBytes(t *testing.T) {
+	key, err := GenerateAESKey()
+	if err != nil {
+		t.Fatalf("GenerateAESKey failed:%s\n", err)
+	}
+	if !LooksLikeValidKeyBytes(key) {
+		t.Errorf("Random key rejected, entropy:%f\n", shannonEntropy(key))
+	}
+	for name, b := range map[string][]byte{
+		"empty":    nil,
+		"all-zero": make([]byte, 32),
+		"repeated": bytes.Repeat([]byte("secret"), 6),
+		"short":    key[:8],
+	} {
+		if LooksLikeValidKeyBytes(b) {
+			t.Errorf("Buffer %s accepted, entropy:%f\n", name, shannonEntropy(b))
+		}
+	}
+}
+
+// EOF
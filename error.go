@@ -21,10 +21,28 @@
 package go_libs
 
 import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
 	"os"
 	"strings"
 )
 
+// Sentinel errors of this package. They are wrapped into the returned errors and can be checked
+// with errors.Is.
+var (
+	// ErrNilKey is returned if a required key is nil.
+	ErrNilKey = errors.New("key is nil")
+	// ErrVerification is returned if a signature does not verify. It is the same as rsa.ErrVerification.
+	ErrVerification = rsa.ErrVerification
+	// ErrParse is returned if an input like a PEM block or a base64 string cannot be decoded or parsed.
+	ErrParse = errors.New("parse error")
+	// ErrWrongPassword is returned if an encrypted private key cannot be decrypted with the password.
+	ErrWrongPassword = errors.New("decryption failed: wrong password?")
+	// ErrWeakKey is returned if a key is smaller than the required minimum size.
+	ErrWeakKey = errors.New("key too weak")
+)
+
 // ErrorExit exits the application with the specified error code. The output is
 // written to the assigned output writer, by default stderr.
 func ErrorExit(errorCode uint8, msg ...string) {
@@ -42,4 +60,23 @@ func ExitIfError(err error, exitcode uint8, msg string) {
 	}
 }
 
+// HTTPStatusForError maps errors of this package to HTTP status codes for web handlers: ErrNilKey
+// (server misconfiguration) to 500, ErrVerification to 401, and ErrParse and ErrWeakKey (bad client
+// input) to 400.
+// Other errors result in 500, and nil in 200.
+func HTTPStatusForError(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrNilKey):
+		return http.StatusInternalServerError
+	case errors.Is(err, ErrVerification):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrParse), errors.Is(err, ErrWeakKey):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // EOF
@@ -0,0 +1,63 @@
+package go_libs
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestHTTPStatusForError(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("status message")
+	sig, err := SignPSSByteArray(key, Sha256bytes2bytes(msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArray failed:%s\n", err)
+	}
+	_, parseErr := Pem2RsaPublicKey([]byte("no PEM"))
+	otherKey := newTestPrivateKey(t)
+	token := signTestJWT(t, key, "", map[string]interface{}{"sub": "status"})
+	_, jwtWrongKeyErr := VerifyJWT(&otherKey.PublicKey, token)
+	_, jwtGarbageErr := VerifyJWT(&key.PublicKey, "garbage")
+	timestamped, err := SignTimestamped(key, msg)
+	if err != nil {
+		t.Fatalf("SignTimestamped failed:%s\n", err)
+	}
+	_, _, timestampShortErr := VerifyTimestampedRange(&key.PublicKey, msg)
+	_, _, timestampWrongKeyErr := VerifyTimestampedRange(&otherKey.PublicKey, timestamped)
+	blob, err := EncryptAndSign(&key.PublicKey, key, msg)
+	if err != nil {
+		t.Fatalf("EncryptAndSign failed:%s\n", err)
+	}
+	_, decryptShortErr := VerifyAndDecrypt(key, &key.PublicKey, msg)
+	_, decryptWrongKeyErr := VerifyAndDecrypt(key, &otherKey.PublicKey, blob)
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"nil", nil, http.StatusOK},
+		{"ErrNilKey", ErrNilKey, http.StatusInternalServerError},
+		{"ErrVerification", ErrVerification, http.StatusUnauthorized},
+		{"ErrParse", ErrParse, http.StatusBadRequest},
+		{"ErrWeakKey", ErrWeakKey, http.StatusBadRequest},
+		{"nil key", VerifyPSSByteArray(nil, sig, msg), http.StatusInternalServerError},
+		{"wrong message", VerifyPSSByteArray(&key.PublicKey, sig, []byte("other")), http.StatusUnauthorized},
+		{"bad base64", VerifyPSSBase64String(&key.PublicKey, "!!!", string(msg)), http.StatusBadRequest},
+		{"bad PEM", parseErr, http.StatusBadRequest},
+		{"JWT wrong key", jwtWrongKeyErr, http.StatusUnauthorized},
+		{"JWT garbage", jwtGarbageErr, http.StatusBadRequest},
+		{"timestamp too short", timestampShortErr, http.StatusBadRequest},
+		{"timestamp wrong key", timestampWrongKeyErr, http.StatusUnauthorized},
+		{"decrypt too short", decryptShortErr, http.StatusBadRequest},
+		{"decrypt wrong key", decryptWrongKeyErr, http.StatusUnauthorized},
+		{"envelope garbage", VerifyEnvelopeJSON(&key.PublicKey, msg, []byte("garbage")), http.StatusBadRequest},
+		{"envelope wrong message", VerifyEnvelopeJSON(&key.PublicKey, []byte("other"), envelopeJSON("PS256", "SHA-256", sig)), http.StatusUnauthorized},
+		{"other", errors.New("other"), http.StatusInternalServerError},
+	} {
+		if status := HTTPStatusForError(tc.err); status != tc.expected {
+			t.Errorf("Status error for %s, is:%d, expected:%d\n", tc.name, status, tc.expected)
+		}
+	}
+}
+
+// EOF
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 func FilenameWithoutSuffix(filename string) string {
@@ -42,4 +43,23 @@ func ByteArray2ReponseWriter(file http.ResponseWriter, bytes []byte) error {
 	return nil
 }
 
+// KeyAge returns the age of a key file based on its modification time. Services can use it to warn
+// when signing keys become too old.
+func KeyAge(filename string) (time.Duration, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return 0, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return time.Since(info.ModTime()), nil
+}
+
+// IsKeyStale reports if the key file is older than maxAge, see KeyAge.
+func IsKeyStale(filename string, maxAge time.Duration) (bool, error) {
+	age, err := KeyAge(filename)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return age > maxAge, nil
+}
+
 // eof
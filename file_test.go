@@ -0,0 +1,37 @@
+package go_libs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsKeyStale(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(filename, []byte("key"), 0600); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	backdated := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filename, backdated, backdated); err != nil {
+		t.Fatalf("Chtimes failed:%s\n", err)
+	}
+	age, err := KeyAge(filename)
+	if err != nil {
+		t.Fatalf("KeyAge failed:%s\n", err)
+	}
+	if age < 47*time.Hour || age > 49*time.Hour {
+		t.Errorf("Age error, is:%s, expected:about 48h\n", age)
+	}
+	if stale, err := IsKeyStale(filename, 24*time.Hour); err != nil || !stale {
+		t.Errorf("Key should be stale after 24h, stale:%t, error:%v\n", stale, err)
+	}
+	if stale, err := IsKeyStale(filename, 72*time.Hour); err != nil || stale {
+		t.Errorf("Key should not be stale after 72h, stale:%t, error:%v\n", stale, err)
+	}
+	if _, err := KeyAge(filename + ".missing"); err == nil {
+		t.Errorf("KeyAge of a missing file should fail\n")
+	}
+}
+
+// EOF
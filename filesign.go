@@ -0,0 +1,110 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// AlgorithmRSAPSSSHA256 is the name of the signature scheme of SignPSSByteArray and SignFile, RSA-PSS with
+// SHA-256, e.g. for WriteDetachedSignature.
+const AlgorithmRSAPSSSHA256 = "RSA-PSS-SHA256"
+
+// format of the files of WriteDetachedSignature:
+//
+//	go_libs-signature v1
+//	algorithm: RSA-PSS-SHA256
+//	<base64 encoded signature>
+const (
+	detachedSignatureMagic   = "go_libs-signature v"
+	detachedSignatureVersion = 1
+	detachedSignatureAlgo    = "algorithm: "
+)
+
+// SignFile streams the file through SHA-256, see Sha256File, and returns the RSA-PSS signature of the
+// digest. The file is not read into memory, so it can be used for large files.
+func SignFile(key *rsa.PrivateKey, filename string) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	digest, err := Sha256File(filename)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	signature, err := SignPSSByteArray(key, digest)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return signature, nil
+}
+
+// VerifyFile verifies a signature of SignFile. The file is streamed through SHA-256 and the digest is
+// verified with VerifyDigestSignature. If no error is returned, then the verification was successful.
+func VerifyFile(key *rsa.PublicKey, filename string, signature []byte) error {
+	if key == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	digest, err := Sha256File(filename)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := VerifyDigestSignature(key, digest, signature); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// WriteDetachedSignature writes the signature, e.g. of SignFile, to sigFile with a small text header
+// recording the format version and the signature algorithm algo, e.g. AlgorithmRSAPSSSHA256. Verifiers
+// can read it with ReadDetachedSignature and know the scheme without out-of-band coordination.
+func WriteDetachedSignature(sigFile string, signature []byte, algo string) error {
+	if len(signature) == 0 {
+		return errors.New(CurrentFunctionName() + ":signature is empty")
+	}
+	if algo == "" || strings.ContainsAny(algo, "\r\n") {
+		return errors.New(CurrentFunctionName() + ":invalid algorithm name " + strconv.Quote(algo))
+	}
+	content := detachedSignatureMagic + strconv.Itoa(detachedSignatureVersion) + "\n" +
+		detachedSignatureAlgo + algo + "\n" +
+		base64.StdEncoding.EncodeToString(signature) + "\n"
+	if err := os.WriteFile(sigFile, []byte(content), 0644); err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return nil
+}
+
+// ReadDetachedSignature reads a signature file of WriteDetachedSignature and returns the signature and
+// its algorithm. Files of an unknown format version are rejected with an error wrapping ErrParse.
+func ReadDetachedSignature(sigFile string) ([]byte, string, error) {
+	buf, err := os.ReadFile(sigFile)
+	if err != nil {
+		return nil, "", errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
+	}
+	lines := strings.Split(strings.TrimRight(string(buf), "\r\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], detachedSignatureMagic) {
+		return nil, "", fmt.Errorf("%s:%w:not a detached signature file", CurrentFunctionName(), ErrParse)
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(lines[0], detachedSignatureMagic))
+	if err != nil || version != detachedSignatureVersion {
+		return nil, "", fmt.Errorf("%s:%w:unsupported detached signature version %s, supported is %d",
+			CurrentFunctionName(), ErrParse, strings.TrimPrefix(lines[0], detachedSignatureMagic), detachedSignatureVersion)
+	}
+	algo := strings.TrimPrefix(lines[1], detachedSignatureAlgo)
+	if !strings.HasPrefix(lines[1], detachedSignatureAlgo) || algo == "" {
+		return nil, "", fmt.Errorf("%s:%w:missing algorithm", CurrentFunctionName(), ErrParse)
+	}
+	signature, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil || len(signature) == 0 {
+		return nil, "", fmt.Errorf("%s:%w:Error, decoding base64 string", CurrentFunctionName(), ErrParse)
+	}
+	return signature, algo, nil
+}
+
+// EOF
@@ -0,0 +1,87 @@
+package go_libs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignVerifyFile(t *testing.T) {
+	key := testPrivateKey(t)
+	filename := filepath.Join(t.TempDir(), "release.tar")
+	content := strings.Repeat("release content\n", 10000)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	sig, err := SignFile(key, filename)
+	if err != nil {
+		t.Fatalf("SignFile failed:%s\n", err)
+	}
+	if err := VerifyFile(&key.PublicKey, filename, sig); err != nil {
+		t.Errorf("VerifyFile failed:%s\n", err)
+	}
+	if err := VerifyPSSByteArray(&key.PublicKey, sig, []byte(content)); err != nil {
+		t.Errorf("File signature does not verify for the content:%s\n", err)
+	}
+	if err := os.WriteFile(filename, []byte(content+"tampered"), 0644); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	if err := VerifyFile(&key.PublicKey, filename, sig); !errors.Is(err, ErrVerification) {
+		t.Errorf("Tampered file error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	if _, err := SignFile(key, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("Signing a missing file should fail\n")
+	}
+	if _, err := SignFile(nil, filename); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+func TestDetachedSignature(t *testing.T) {
+	key := testPrivateKey(t)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "release.tar")
+	if err := os.WriteFile(filename, []byte("release"), 0644); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	sig, err := SignFile(key, filename)
+	if err != nil {
+		t.Fatalf("SignFile failed:%s\n", err)
+	}
+	sigFile := filename + ".sig"
+	if err := WriteDetachedSignature(sigFile, sig, AlgorithmRSAPSSSHA256); err != nil {
+		t.Fatalf("WriteDetachedSignature failed:%s\n", err)
+	}
+	readSig, algo, err := ReadDetachedSignature(sigFile)
+	if err != nil {
+		t.Fatalf("ReadDetachedSignature failed:%s\n", err)
+	}
+	if algo != AlgorithmRSAPSSSHA256 {
+		t.Errorf("Algorithm error, is:%s, expected:%s\n", algo, AlgorithmRSAPSSSHA256)
+	}
+	if err := VerifyFile(&key.PublicKey, filename, readSig); err != nil {
+		t.Errorf("VerifyFile failed for the read signature:%s\n", err)
+	}
+
+	content, _ := os.ReadFile(sigFile)
+	for name, data := range map[string]string{
+		"future version": strings.Replace(string(content), "signature v1", "signature v2", 1),
+		"no header":      "algorithm: RSA-PSS-SHA256\nAAAA\n",
+		"bad base64":     "go_libs-signature v1\nalgorithm: RSA-PSS-SHA256\n!!!\n",
+	} {
+		broken := filepath.Join(dir, "broken.sig")
+		if err := os.WriteFile(broken, []byte(data), 0644); err != nil {
+			t.Fatalf("WriteFile failed:%s\n", err)
+		}
+		if _, _, err := ReadDetachedSignature(broken); !errors.Is(err, ErrParse) {
+			t.Errorf("Error for %s, is:%v, expected:%v\n", name, err, ErrParse)
+		}
+	}
+	if err := WriteDetachedSignature(sigFile, sig, "two\nlines"); err == nil {
+		t.Errorf("Algorithm with a line break should be rejected\n")
+	}
+}
+
+// EOF
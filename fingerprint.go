@@ -0,0 +1,82 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// shortFingerprintLength is the number of hex characters of the fingerprint shown in error messages.
+const shortFingerprintLength = 16
+
+// globalFingerprintInErrors stores if signing errors contain the fingerprint of the key. This variable
+// should never be set directly, use FingerprintInErrors.
+var globalFingerprintInErrors atomic.Value
+
+// init makes sure that signing errors do not contain fingerprints by default.
+func init() {
+	globalFingerprintInErrors.Store(false)
+}
+
+// FingerprintInErrors lets us add the short fingerprint of the public key to the errors of the signing
+// functions like SignPSSByteArray. This helps to identify the key in services using multiple keys.
+// The private key material is never part of an error.
+func FingerprintInErrors(val bool) {
+	globalFingerprintInErrors.Store(val)
+}
+
+// signingError creates the error of a failed signing operation in function fn. If enabled by
+// FingerprintInErrors, the short fingerprint of the key is included.
+func signingError(fn string, key *rsa.PrivateKey, err error) error {
+	if globalFingerprintInErrors.Load().(bool) {
+		if fp, fpErr := PrivateKeyFingerprint(key); fpErr == nil {
+			return fmt.Errorf("%s:key %s:%w", fn, fp[:shortFingerprintLength], err)
+		}
+	}
+	return fmt.Errorf("%s:%w", fn, err)
+}
+
+// PublicKeyFingerprint returns the SHA-256 digest of the PKIX (DER) encoded public key as a lowercase
+// hex string of 64 characters without separators. This is the same value as shown by
+// tlsRsaPubFingerprint, see README.md. The format is stable, so fingerprints can be compared in logs
+// and across key rotations. See PublicKeyFingerprintColon for a colon-separated variant.
+func PublicKeyFingerprint(pub *rsa.PublicKey) (string, error) {
+	if pub == nil {
+		return "", fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(der)), nil
+}
+
+// PublicKeyFingerprintColon returns the fingerprint of PublicKeyFingerprint as colon-separated lowercase
+// hex bytes, e.g. 5d:30:61:...:7c, as shown by openssl or in certificate viewers. The format is stable.
+func PublicKeyFingerprintColon(pub *rsa.PublicKey) (string, error) {
+	fp, err := PublicKeyFingerprint(pub)
+	if err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	pairs := make([]string, 0, len(fp)/2)
+	for i := 0; i < len(fp); i += 2 {
+		pairs = append(pairs, fp[i:i+2])
+	}
+	return strings.Join(pairs, ":"), nil
+}
+
+// PrivateKeyFingerprint returns the fingerprint of the public key contained in the private key. By design,
+// the fingerprints of the private and the public key of a pair are equal, so both can be used to
+// deduplicate keys in storage.
+func PrivateKeyFingerprint(priv *rsa.PrivateKey) (string, error) {
+	if priv == nil {
+		return "", fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	return PublicKeyFingerprint(&priv.PublicKey)
+}
+
+// EOF
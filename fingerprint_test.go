@@ -0,0 +1,69 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPrivateKeyFingerprintMatchesPublic(t *testing.T) {
+	key := testPrivateKey(t)
+	privFP, err := PrivateKeyFingerprint(key)
+	if err != nil {
+		t.Fatalf("PrivateKeyFingerprint failed:%s\n", err)
+	}
+	pubFP, err := PublicKeyFingerprint(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("PublicKeyFingerprint failed:%s\n", err)
+	}
+	if privFP != pubFP {
+		t.Errorf("Fingerprint error, private:%s, public:%s\n", privFP, pubFP)
+	}
+	if len(pubFP) != 64 {
+		t.Errorf("Fingerprint length error, is:%d, expected:64\n", len(pubFP))
+	}
+}
+
+func TestFingerprintInSigningErrors(t *testing.T) {
+	key := *testPrivateKey(t)
+	// primes not matching the modulus make signing fail
+	key.Primes = []*big.Int{big.NewInt(3), big.NewInt(5)}
+	key.Precomputed = rsa.PrecomputedValues{}
+	fp, err := PrivateKeyFingerprint(&key)
+	if err != nil {
+		t.Fatalf("PrivateKeyFingerprint failed:%s\n", err)
+	}
+	digest := Sha256bytes2bytes([]byte("message"))
+	if _, err := SignPSSByteArray(&key, digest); err == nil || strings.Contains(err.Error(), fp[:shortFingerprintLength]) {
+		t.Errorf("Signing error should fail without fingerprint by default, is:%v\n", err)
+	}
+	FingerprintInErrors(true)
+	defer FingerprintInErrors(false)
+	if _, err := SignPSSByteArray(&key, digest); err == nil || !strings.Contains(err.Error(), fp[:shortFingerprintLength]) {
+		t.Errorf("Signing error should contain the fingerprint %s, is:%v\n", fp[:shortFingerprintLength], err)
+	} else if errors.Unwrap(err) == nil {
+		t.Errorf("Signing error should wrap the cause, is:%v\n", err)
+	}
+}
+
+func TestPublicKeyFingerprintColon(t *testing.T) {
+	key := testPrivateKey(t)
+	fp, err := PublicKeyFingerprint(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("PublicKeyFingerprint failed:%s\n", err)
+	}
+	colon, err := PublicKeyFingerprintColon(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("PublicKeyFingerprintColon failed:%s\n", err)
+	}
+	if len(colon) != 95 || strings.ReplaceAll(colon, ":", "") != fp {
+		t.Errorf("Colon fingerprint error, is:%s, expected the bytes of:%s\n", colon, fp)
+	}
+	if _, err := PublicKeyFingerprintColon(nil); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+// EOF
@@ -1,3 +1,7 @@
 module github.com/engelch/go_libs/v2
 
 go 1.17
+
+require golang.org/x/crypto v0.17.0
+
+require golang.org/x/sys v0.15.0 // indirect
@@ -0,0 +1,177 @@
+package go_libs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1"   // register SHA-1 for crypto.Hash, only used if allowed by AllowSHA1
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for crypto.Hash
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// globalAllowSHA1 stores if SHA-1 based signatures are accepted. This variable should never be set
+// directly, use AllowSHA1.
+var globalAllowSHA1 atomic.Value
+
+// init makes sure that SHA-1 is refused by default.
+func init() {
+	globalAllowSHA1.Store(false)
+}
+
+// AllowSHA1 allows us to accept SHA-1 digests for signature verification. SHA-1 is refused by default
+// to prevent downgrade attacks. Only turn it on for legacy partners.
+func AllowSHA1(val bool) {
+	globalAllowSHA1.Store(val)
+}
+
+// hashByName maps names like SHA-256, sha256, or SHA512 to the corresponding crypto.Hash. SHA-1 is
+// only supported if allowed by AllowSHA1.
+func hashByName(name string) (crypto.Hash, error) {
+	switch strings.ReplaceAll(strings.ToUpper(name), "-", "") {
+	case "SHA1":
+		if !globalAllowSHA1.Load().(bool) {
+			return 0, fmt.Errorf("%w:SHA-1 is refused, see AllowSHA1", ErrParse)
+		}
+		return crypto.SHA1, nil
+	case "SHA256":
+		return crypto.SHA256, nil
+	case "SHA384":
+		return crypto.SHA384, nil
+	case "SHA512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("%w:unsupported digest algorithm %s", ErrParse, name)
+	}
+}
+
+// HashAlgo selects the hash algorithm of HashBytes and the RSA-PSS functions SignPSSByteArrayHash and
+// VerifyPSSByteArrayHash. The zero value is SHA-256, the default of this package.
+type HashAlgo int
+
+// supported hash algorithms
+const (
+	HashSHA256 HashAlgo = iota
+	HashSHA384
+	HashSHA512
+)
+
+// cryptoHash returns the crypto.Hash of the algorithm.
+func (algo HashAlgo) cryptoHash() (crypto.Hash, error) {
+	switch algo {
+	case HashSHA256:
+		return crypto.SHA256, nil
+	case HashSHA384:
+		return crypto.SHA384, nil
+	case HashSHA512:
+		return crypto.SHA512, nil
+	default:
+		return 0, errors.New("unsupported hash algorithm " + algo.String())
+	}
+}
+
+// String returns the name of the algorithm, e.g. SHA-256.
+func (algo HashAlgo) String() string {
+	switch algo {
+	case HashSHA256:
+		return "SHA-256"
+	case HashSHA384:
+		return "SHA-384"
+	case HashSHA512:
+		return "SHA-512"
+	default:
+		return "HashAlgo(" + strconv.Itoa(int(algo)) + ")"
+	}
+}
+
+// HashBytes returns the digest of the data with the algorithm. HashBytes(HashSHA256, data) is the same as
+// Sha256bytes2bytes(data). Unsupported algorithms return nil.
+func HashBytes(algo HashAlgo, data []byte) []byte {
+	hash, err := algo.cryptoHash()
+	if err != nil {
+		return nil
+	}
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// SignPSSByteArrayHash works like SignPSSByteArray, but for a digest created with the algorithm, see
+// HashBytes. The same algorithm is used for the PSS options.
+func SignPSSByteArrayHash(key *rsa.PrivateKey, algo HashAlgo, digest []byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	hash, err := algo.cryptoHash()
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	signature, err := rsa.SignPSS(rand.Reader, key, hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: hash})
+	if err != nil {
+		return nil, signingError(CurrentFunctionName(), key, err)
+	}
+	return signature, nil
+}
+
+// VerifyPSSByteArrayHash works like VerifyPSSByteArray, but recalculates the digest of the message with
+// the algorithm, which is also used for the PSS options. If no error is returned, then the verification
+// was successful.
+func VerifyPSSByteArrayHash(key *rsa.PublicKey, algo HashAlgo, sig []byte, msg []byte) error {
+	if key == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	hash, err := algo.cryptoHash()
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := rsa.VerifyPSS(key, hash, HashBytes(algo, msg), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: hash}); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// RecommendedHash returns the hash to use with a key: SHA-512 for keys of 4096 bits and more and
+// SHA-256 for smaller keys like 2048 or 3072 bits. A nil key also yields SHA-256.
+func RecommendedHash(pub *rsa.PublicKey) crypto.Hash {
+	if pub != nil && pub.N != nil && pub.N.BitLen() >= 4096 {
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+// VerifyMultiHash verifies an RSA-PSS signature of msg which may have been created with any of the
+// hashes, e.g. SHA-256 or SHA-512 for heterogeneous signers. The hashes are tried in order until one
+// verifies. SHA-1 is only accepted if allowed by AllowSHA1, MD5 never. If no error is returned, the
+// verification was successful.
+func VerifyMultiHash(pub *rsa.PublicKey, msg string, sig []byte, hashes []crypto.Hash) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	for _, hash := range hashes {
+		switch hash {
+		case crypto.MD4, crypto.MD5, crypto.MD5SHA1:
+			return fmt.Errorf("%s:%w:hash %s is refused", CurrentFunctionName(), ErrParse, hash.String())
+		case crypto.SHA1:
+			if !globalAllowSHA1.Load().(bool) {
+				return fmt.Errorf("%s:%w:SHA-1 is refused, see AllowSHA1", CurrentFunctionName(), ErrParse)
+			}
+		}
+	}
+	for _, hash := range hashes {
+		if !hash.Available() {
+			return errors.New(CurrentFunctionName() + ":hash " + hash.String() + " not available")
+		}
+		h := hash.New()
+		h.Write([]byte(msg))
+		if rsa.VerifyPSS(pub, hash, h.Sum(nil), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s:%w", CurrentFunctionName(), ErrVerification)
+}
+
+// EOF
@@ -0,0 +1,120 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSHA1RefusedUnlessAllowed(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("legacy message")
+	digest := sha1.Sum(msg)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15 failed:%s\n", err)
+	}
+	env := envelopeJSON("RS1", "SHA-1", sig)
+	if err := VerifyEnvelopeJSON(&key.PublicKey, msg, env); err == nil {
+		t.Errorf("SHA-1 signature should be refused by default\n")
+	}
+	AllowSHA1(true)
+	defer AllowSHA1(false)
+	if err := VerifyEnvelopeJSON(&key.PublicKey, msg, env); err != nil {
+		t.Errorf("SHA-1 signature should verify if allowed:%s\n", err)
+	}
+}
+
+func TestRecommendedHash(t *testing.T) {
+	tests := []struct {
+		bits     int
+		expected crypto.Hash
+	}{
+		{2048, crypto.SHA256},
+		{3072, crypto.SHA256},
+		{4096, crypto.SHA512},
+		{8192, crypto.SHA512},
+	}
+	for _, test := range tests {
+		// only the size of the modulus matters, so a power of two is sufficient
+		pub := &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), uint(test.bits-1)), E: 65537}
+		if hash := RecommendedHash(pub); hash != test.expected {
+			t.Errorf("RecommendedHash for %d bits error, is:%s, expected:%s\n", test.bits, hash, test.expected)
+		}
+	}
+}
+
+func TestVerifyMultiHash(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := "signed with SHA-512"
+	digest := sha512.Sum512([]byte(msg))
+	sig, err := rsa.SignPSS(rand.Reader, key, crypto.SHA512, digest[:], nil)
+	if err != nil {
+		t.Fatalf("SignPSS failed:%s\n", err)
+	}
+	if err := VerifyMultiHash(&key.PublicKey, msg, sig, []crypto.Hash{crypto.SHA256, crypto.SHA512}); err != nil {
+		t.Errorf("VerifyMultiHash failed:%s\n", err)
+	}
+	if err := VerifyMultiHash(&key.PublicKey, msg, sig, []crypto.Hash{crypto.SHA256}); !errors.Is(err, ErrVerification) {
+		t.Errorf("VerifyMultiHash without SHA-512 error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+}
+
+func TestVerifyMultiHashRefusesWeakHashes(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := "signed with SHA-1"
+	digest := sha1.Sum([]byte(msg))
+	sig, err := rsa.SignPSS(rand.Reader, key, crypto.SHA1, digest[:], nil)
+	if err != nil {
+		t.Fatalf("SignPSS failed:%s\n", err)
+	}
+	if err := VerifyMultiHash(&key.PublicKey, msg, sig, []crypto.Hash{crypto.SHA256, crypto.SHA1}); !errors.Is(err, ErrParse) {
+		t.Errorf("SHA-1 by default error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	AllowSHA1(true)
+	defer AllowSHA1(false)
+	if err := VerifyMultiHash(&key.PublicKey, msg, sig, []crypto.Hash{crypto.SHA256, crypto.SHA1}); err != nil {
+		t.Errorf("VerifyMultiHash with allowed SHA-1 failed:%s\n", err)
+	}
+	if err := VerifyMultiHash(&key.PublicKey, msg, sig, []crypto.Hash{crypto.MD5}); !errors.Is(err, ErrParse) {
+		t.Errorf("MD5 error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+func TestHashBytes(t *testing.T) {
+	data := []byte("abc")
+	if !bytes.Equal(HashBytes(HashSHA256, data), Sha256bytes2bytes(data)) {
+		t.Errorf("HashBytes(HashSHA256) differs from Sha256bytes2bytes\n")
+	}
+	for algo, size := range map[HashAlgo]int{HashSHA256: 32, HashSHA384: 48, HashSHA512: 64} {
+		if digest := HashBytes(algo, data); len(digest) != size {
+			t.Errorf("Digest length of %s error, is:%d, expected:%d\n", algo, len(digest), size)
+		}
+	}
+	if HashBytes(HashAlgo(42), data) != nil {
+		t.Errorf("Unsupported algorithm should return nil\n")
+	}
+}
+
+func TestPSSByteArrayHash(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("SHA-512 partner")
+	sig, err := SignPSSByteArrayHash(key, HashSHA512, HashBytes(HashSHA512, msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArrayHash failed:%s\n", err)
+	}
+	if err := VerifyPSSByteArrayHash(&key.PublicKey, HashSHA512, sig, msg); err != nil {
+		t.Errorf("VerifyPSSByteArrayHash failed:%s\n", err)
+	}
+	if err := VerifyPSSByteArrayHash(&key.PublicKey, HashSHA256, sig, msg); !errors.Is(err, ErrVerification) {
+		t.Errorf("Verification with another algorithm error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+}
+
+// EOF
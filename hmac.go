@@ -0,0 +1,34 @@
+package go_libs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// SecureCompare reports if a and b are equal, e.g. digests, MACs, or tokens. The comparison takes the
+// same time for all inputs of the same length, so an attacker cannot learn how many leading bytes of a
+// guess are correct. Do not replace it by bytes.Equal, which returns at the first difference and leaks
+// this through its timing. Only the length of the inputs is not secret.
+func SecureCompare(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// HMACSha256 returns the HMAC-SHA256 tag of the message for integrations with a pre-shared secret key
+// instead of RSA keys.
+func HMACSha256(key, msg []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSha256 reports if the tag is the HMAC-SHA256 tag of the message, see HMACSha256. The tags are
+// compared in constant time.
+func VerifyHMACSha256(key, msg, tag []byte) bool {
+	return SecureCompare(HMACSha256(key, msg), tag)
+}
+
+// EOF
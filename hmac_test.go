@@ -0,0 +1,45 @@
+package go_libs
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestHMACSha256(t *testing.T) {
+	// RFC 4231, test case 2
+	tag := HMACSha256([]byte("Jefe"), []byte("what do ya want for nothing?"))
+	const expected = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if hex.EncodeToString(tag) != expected {
+		t.Errorf("HMACSha256 error, is:%x, expected:%s\n", tag, expected)
+	}
+
+	key := []byte("pre-shared secret")
+	msg := []byte("webhook payload")
+	tag = HMACSha256(key, msg)
+	if !VerifyHMACSha256(key, msg, tag) {
+		t.Errorf("VerifyHMACSha256 failed for a valid tag\n")
+	}
+	flipped := append([]byte{}, msg...)
+	flipped[0] ^= 0x01
+	if VerifyHMACSha256(key, flipped, tag) {
+		t.Errorf("VerifyHMACSha256 accepted a message with a flipped bit\n")
+	}
+}
+
+func TestSecureCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     []byte
+		expected bool
+	}{
+		{[]byte("digest"), []byte("digest"), true},
+		{[]byte("digest"), []byte("digesT"), false},
+		{[]byte("digest"), []byte("digest!"), false},
+		{nil, []byte{}, true},
+	} {
+		if is := SecureCompare(tc.a, tc.b); is != tc.expected {
+			t.Errorf("SecureCompare(%q, %q) error, is:%v, expected:%v\n", tc.a, tc.b, is, tc.expected)
+		}
+	}
+}
+
+// EOF
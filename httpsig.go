@@ -0,0 +1,200 @@
+package go_libs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTP message signatures (RFC 9421) are created with the algorithm rsa-pss-sha512 and the label sig1.
+const (
+	httpSignatureLabel = "sig1"
+	httpSignatureAlg   = "rsa-pss-sha512"
+)
+
+// httpSignaturePSSOptions are the RSA-PSS parameters required by rsa-pss-sha512: a salt of 64 bytes.
+var httpSignaturePSSOptions = &rsa.PSSOptions{SaltLength: 64, Hash: crypto.SHA512}
+
+// httpComponentValue returns the value of a component of the request. Supported are the derived
+// components @method, @authority, @path, and @query and header fields given by their name.
+func httpComponentValue(req *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return strings.ToUpper(req.Method), nil
+	case "@authority", "host":
+		host := req.Host
+		if host == "" && req.URL != nil {
+			host = req.URL.Host
+		}
+		if host == "" {
+			return "", errors.New("request has no host")
+		}
+		return strings.ToLower(host), nil
+	case "@path":
+		if req.URL == nil {
+			return "", errors.New("request has no URL")
+		}
+		if path := req.URL.EscapedPath(); path != "" {
+			return path, nil
+		}
+		return "/", nil
+	case "@query":
+		if req.URL == nil {
+			return "", errors.New("request has no URL")
+		}
+		return "?" + req.URL.RawQuery, nil
+	}
+	if strings.HasPrefix(component, "@") {
+		return "", errors.New("unsupported derived component " + component)
+	}
+	values := req.Header.Values(component)
+	if len(values) == 0 {
+		return "", errors.New("header " + component + " missing")
+	}
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return strings.Join(values, ", "), nil
+}
+
+// httpSignatureBase creates the signature base of RFC 9421, section 2.5, from the components and the
+// serialised signature parameters.
+func httpSignatureBase(req *http.Request, components []string, params string) (string, error) {
+	var base strings.Builder
+	for _, component := range components {
+		value, err := httpComponentValue(req, component)
+		if err != nil {
+			return "", err
+		}
+		base.WriteString(strconv.Quote(component) + ": " + value + "\n")
+	}
+	base.WriteString(`"@signature-params": ` + params)
+	return base.String(), nil
+}
+
+// SignHTTPRequest signs the request as an HTTP message signature (RFC 9421) with rsa-pss-sha512. The
+// signature covers the listed components, e.g. @method, @authority, @path, @query, and lowercase
+// header names like content-type. It sets the headers Signature-Input and Signature with the label
+// sig1. Use VerifyHTTPRequest for verification.
+func SignHTTPRequest(key *rsa.PrivateKey, req *http.Request, components []string) error {
+	if key == nil {
+		return fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	lower := make([]string, len(components))
+	quoted := make([]string, len(components))
+	for i, component := range components {
+		lower[i] = strings.ToLower(component)
+		quoted[i] = strconv.Quote(lower[i])
+	}
+	params := "(" + strings.Join(quoted, " ") + ");created=" + strconv.FormatInt(time.Now().Unix(), 10) +
+		";alg=\"" + httpSignatureAlg + "\""
+	base, err := httpSignatureBase(req, lower, params)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	digest := sha512.Sum512([]byte(base))
+	sig, err := rsa.SignPSS(rand.Reader, key, crypto.SHA512, digest[:], httpSignaturePSSOptions)
+	if err != nil {
+		return signingError(CurrentFunctionName(), key, err)
+	}
+	req.Header.Set("Signature-Input", httpSignatureLabel+"="+params)
+	req.Header.Set("Signature", httpSignatureLabel+"=:"+base64.StdEncoding.EncodeToString(sig)+":")
+	return nil
+}
+
+// parseHTTPSignatureInput returns the covered components, the serialised signature parameters, and the
+// creation time of the Signature-Input header created by SignHTTPRequest.
+func parseHTTPSignatureInput(input string) ([]string, string, time.Time, error) {
+	params := strings.TrimPrefix(input, httpSignatureLabel+"=")
+	if params == input || !strings.HasPrefix(params, "(") {
+		return nil, "", time.Time{}, errors.New("Signature-Input without " + httpSignatureLabel)
+	}
+	end := strings.Index(params, ")")
+	if end < 0 {
+		return nil, "", time.Time{}, errors.New("Signature-Input without component list")
+	}
+	if !strings.Contains(params[end:], `;alg="`+httpSignatureAlg+`"`) {
+		return nil, "", time.Time{}, errors.New("Signature-Input without alg " + httpSignatureAlg)
+	}
+	var components []string
+	for _, quoted := range strings.Fields(params[1:end]) {
+		component, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, "", time.Time{}, errors.New("invalid component " + quoted)
+		}
+		components = append(components, component)
+	}
+	created := strings.TrimPrefix(params[end:], ");created=")
+	if created == params[end:] {
+		return nil, "", time.Time{}, errors.New("Signature-Input without created")
+	}
+	if i := strings.Index(created, ";"); i >= 0 {
+		created = created[:i]
+	}
+	unix, err := strconv.ParseInt(created, 10, 64)
+	if err != nil {
+		return nil, "", time.Time{}, errors.New("invalid created " + created)
+	}
+	return components, params, time.Unix(unix, 0), nil
+}
+
+// VerifyHTTPRequest verifies the HTTP message signature (RFC 9421) with the label sig1 of the request,
+// as created by SignHTTPRequest. The list of covered components is chosen by the sender, so the
+// signature must cover at least the requiredComponents, e.g. @method, @authority, and @path. Signatures
+// created (parameter created) more than maxAge ago or more than a minute in the future are rejected.
+// If no error is returned, the verification was successful.
+func VerifyHTTPRequest(pub *rsa.PublicKey, req *http.Request, requiredComponents []string, maxAge time.Duration) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	components, params, created, err := parseHTTPSignatureInput(req.Header.Get("Signature-Input"))
+	if err != nil {
+		return fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	for _, required := range requiredComponents {
+		if !containsString(components, strings.ToLower(required)) {
+			return fmt.Errorf("%s:%w:signature does not cover %s", CurrentFunctionName(), ErrVerification, required)
+		}
+	}
+	if err := checkTimestampAge(created, maxAge); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	sigHeader := req.Header.Get("Signature")
+	sigB64 := strings.TrimSuffix(strings.TrimPrefix(sigHeader, httpSignatureLabel+"=:"), ":")
+	if len(sigB64) >= len(sigHeader) {
+		return fmt.Errorf("%s:%w:Signature without %s", CurrentFunctionName(), ErrParse, httpSignatureLabel)
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return fmt.Errorf("%s:%w:decoding base64 string", CurrentFunctionName(), ErrParse)
+	}
+	base, err := httpSignatureBase(req, components, params)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	digest := sha512.Sum512([]byte(base))
+	if err := rsa.VerifyPSS(pub, crypto.SHA512, digest[:], sig, httpSignaturePSSOptions); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// containsString returns true if the list contains the string.
+func containsString(list []string, s string) bool {
+	for _, element := range list {
+		if element == s {
+			return true
+		}
+	}
+	return false
+}
+
+// EOF
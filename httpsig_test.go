@@ -0,0 +1,70 @@
+package go_libs
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPRequestSignature(t *testing.T) {
+	key := testPrivateKey(t)
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/v1/orders?limit=10", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed:%s\n", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	required := []string{"@method", "@authority", "@path"}
+	if err := SignHTTPRequest(key, req, []string{"@method", "@authority", "@path", "@query", "accept"}); err != nil {
+		t.Fatalf("SignHTTPRequest failed:%s\n", err)
+	}
+	if req.Header.Get("Signature") == "" || req.Header.Get("Signature-Input") == "" {
+		t.Fatalf("Signature headers not set:%v\n", req.Header)
+	}
+	if err := VerifyHTTPRequest(&key.PublicKey, req, required, time.Minute); err != nil {
+		t.Errorf("VerifyHTTPRequest failed:%s\n", err)
+	}
+	req.URL.RawQuery = "limit=1000"
+	if err := VerifyHTTPRequest(&key.PublicKey, req, required, time.Minute); !errors.Is(err, ErrVerification) {
+		t.Errorf("Modified query error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	req.Header.Del("Accept")
+	if err := VerifyHTTPRequest(&key.PublicKey, req, required, time.Minute); err == nil {
+		t.Errorf("Missing covered header should fail verification\n")
+	}
+}
+
+func TestHTTPRequestSignaturePolicy(t *testing.T) {
+	key := testPrivateKey(t)
+	req, err := http.NewRequest(http.MethodPost, "https://api.example.com/v1/orders", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed:%s\n", err)
+	}
+	if err := SignHTTPRequest(key, req, []string{}); err != nil {
+		t.Fatalf("SignHTTPRequest failed:%s\n", err)
+	}
+	if err := VerifyHTTPRequest(&key.PublicKey, req, []string{"@method", "@path"}, time.Minute); !errors.Is(err, ErrVerification) {
+		t.Errorf("Empty component list error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	if err := SignHTTPRequest(key, req, []string{"@method", "@path"}); err != nil {
+		t.Fatalf("SignHTTPRequest failed:%s\n", err)
+	}
+	if err := VerifyHTTPRequest(&key.PublicKey, req, []string{"@method", "@path"}, time.Minute); err != nil {
+		t.Errorf("VerifyHTTPRequest failed:%s\n", err)
+	}
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	input := req.Header.Get("Signature-Input")
+	req.Header.Set("Signature-Input", strings.Replace(input, "created=", "created="+old+";x=", 1))
+	if err := VerifyHTTPRequest(&key.PublicKey, req, nil, time.Minute); !errors.Is(err, ErrVerification) || !strings.Contains(err.Error(), "too old") {
+		t.Errorf("Old signature error, is:%v, expected a too old error\n", err)
+	}
+	req.Header.Set("Signature-Input", input)
+	req.URL = nil
+	if err := VerifyHTTPRequest(&key.PublicKey, req, nil, time.Minute); err == nil {
+		t.Errorf("Request without URL should fail verification\n")
+	}
+}
+
+// EOF
@@ -0,0 +1,126 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// hybridKeyLengthSize is the size of the big-endian length prefix of the RSA-wrapped AES key.
+const hybridKeyLengthSize = 2
+
+// HybridEncrypt encrypts plaintexts of any size for the owner of the public key. A random AES-256 key
+// encrypts the plaintext with EncryptAES256, and the AES key itself is encrypted with RSA-OAEP/SHA-256.
+// The result is: length of wrapped key (2 bytes, big-endian) || wrapped key || nonce || ciphertext || tag
+func HybridEncrypt(pub *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+	if pub == nil {
+		return nil, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	aesKey := make([]byte, aes256KeySize)
+	if _, err := io.ReadFull(rand.Reader, aesKey); err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":key creation:" + err.Error())
+	}
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, aesKey, nil)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":key wrapping:" + err.Error())
+	}
+	ciphertext, err := EncryptAES256(aesKey, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	blob := make([]byte, hybridKeyLengthSize, hybridKeyLengthSize+len(wrappedKey)+len(ciphertext))
+	binary.BigEndian.PutUint16(blob, uint16(len(wrappedKey)))
+	blob = append(blob, wrappedKey...)
+	return append(blob, ciphertext...), nil
+}
+
+// HybridDecrypt decrypts a blob created by HybridEncrypt with the private key.
+func HybridDecrypt(priv *rsa.PrivateKey, blob []byte) ([]byte, error) {
+	if priv == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if len(blob) < hybridKeyLengthSize {
+		return nil, fmt.Errorf("%s:%w:blob too short", CurrentFunctionName(), ErrParse)
+	}
+	keyLen := int(binary.BigEndian.Uint16(blob))
+	if len(blob) < hybridKeyLengthSize+keyLen {
+		return nil, fmt.Errorf("%s:%w:blob too short", CurrentFunctionName(), ErrParse)
+	}
+	wrappedKey := blob[hybridKeyLengthSize : hybridKeyLengthSize+keyLen]
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, wrappedKey, nil)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":key unwrapping failed, wrong key?")
+	}
+	plaintext, err := DecryptAES256(aesKey, blob[hybridKeyLengthSize+keyLen:])
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return plaintext, nil
+}
+
+// SealEnvelope encrypts plaintexts of any size, also empty ones, for the owner of the public key, e.g.
+// a key created by CreateRSAKeyPair. It is the envelope encryption of HybridEncrypt, see there for the
+// self-describing format of the blob. Use OpenEnvelope for decryption.
+func SealEnvelope(pub *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+	return HybridEncrypt(pub, plaintext)
+}
+
+// OpenEnvelope decrypts a blob created by SealEnvelope or HybridEncrypt with the private key. Truncated
+// or modified blobs and a wrong key result in an error.
+func OpenEnvelope(priv *rsa.PrivateKey, blob []byte) ([]byte, error) {
+	return HybridDecrypt(priv, blob)
+}
+
+// HybridEncryptedSize returns the exact length of the output of HybridEncrypt for a plaintext of the
+// given length: length prefix + wrapped key (key size) + nonce + ciphertext (plaintext length) + tag
+// For a nil key 0 is returned, as HybridEncrypt fails for it.
+func HybridEncryptedSize(pub *rsa.PublicKey, plaintextLen int) int {
+	if pub == nil || pub.N == nil {
+		return 0
+	}
+	return hybridKeyLengthSize + pub.Size() + aesGCMNonceSize + plaintextLen + aesGCMTagSize
+}
+
+// EncryptAndSign encrypts the plaintext for the recipient with HybridEncrypt and signs the ciphertext
+// with the private key of the sender (RSA-PSS). The result is ciphertext || signature.
+func EncryptAndSign(recipientPub *rsa.PublicKey, senderPriv *rsa.PrivateKey, plaintext []byte) ([]byte, error) {
+	if senderPriv == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	ciphertext, err := HybridEncrypt(recipientPub, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	sig, err := SignPSSByteArray(senderPriv, Sha256bytes2bytes(ciphertext))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return append(ciphertext, sig...), nil
+}
+
+// VerifyAndDecrypt verifies the signature of the sender over a blob created by EncryptAndSign and only
+// then decrypts it with the private key of the recipient.
+func VerifyAndDecrypt(recipientPriv *rsa.PrivateKey, senderPub *rsa.PublicKey, blob []byte) ([]byte, error) {
+	if senderPub == nil {
+		return nil, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	sigLen := senderPub.Size()
+	if len(blob) < sigLen {
+		return nil, fmt.Errorf("%s:%w:blob too short", CurrentFunctionName(), ErrParse)
+	}
+	ciphertext, sig := blob[:len(blob)-sigLen], blob[len(blob)-sigLen:]
+	if err := VerifyPSSByteArray(senderPub, sig, ciphertext); err != nil {
+		return nil, fmt.Errorf("%s:signature verification:%w", CurrentFunctionName(), err)
+	}
+	plaintext, err := HybridDecrypt(recipientPriv, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return plaintext, nil
+}
+
+// EOF
@@ -0,0 +1,87 @@
+package go_libs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSealEnvelope(t *testing.T) {
+	key := testPrivateKey(t)
+	for _, plaintext := range [][]byte{{}, []byte("short"), bytes.Repeat([]byte("large "), 10000)} {
+		blob, err := SealEnvelope(&key.PublicKey, plaintext)
+		if err != nil {
+			t.Fatalf("SealEnvelope failed:%s\n", err)
+		}
+		opened, err := OpenEnvelope(key, blob)
+		if err != nil {
+			t.Fatalf("OpenEnvelope of %d bytes failed:%s\n", len(plaintext), err)
+		}
+		if !bytes.Equal(opened, plaintext) {
+			t.Errorf("Round-trip of %d bytes error, is:%d bytes\n", len(plaintext), len(opened))
+		}
+	}
+}
+
+func TestOpenEnvelopeErrors(t *testing.T) {
+	key := testPrivateKey(t)
+	blob, err := SealEnvelope(&key.PublicKey, []byte("envelope"))
+	if err != nil {
+		t.Fatalf("SealEnvelope failed:%s\n", err)
+	}
+	for _, n := range []int{0, 1, hybridKeyLengthSize + 10, hybridKeyLengthSize + key.Size(), len(blob) - 1} {
+		if _, err := OpenEnvelope(key, blob[:n]); err == nil {
+			t.Errorf("Blob truncated to %d bytes should be rejected\n", n)
+		}
+	}
+	if _, err := OpenEnvelope(newTestPrivateKey(t), blob); err == nil {
+		t.Errorf("Opening with a wrong key should fail\n")
+	}
+}
+
+func TestEncryptAndSignRoundTrip(t *testing.T) {
+	recipient := testPrivateKey(t)
+	sender := newTestPrivateKey(t)
+	plaintext := []byte("confidential and authenticated")
+	blob, err := EncryptAndSign(&recipient.PublicKey, sender, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptAndSign failed:%s\n", err)
+	}
+	decrypted, err := VerifyAndDecrypt(recipient, &sender.PublicKey, blob)
+	if err != nil {
+		t.Fatalf("VerifyAndDecrypt failed:%s\n", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Round-trip error, is:%s, expected:%s\n", decrypted, plaintext)
+	}
+}
+
+func TestVerifyAndDecryptTampered(t *testing.T) {
+	recipient := testPrivateKey(t)
+	sender := newTestPrivateKey(t)
+	blob, err := EncryptAndSign(&recipient.PublicKey, sender, []byte("do not modify"))
+	if err != nil {
+		t.Fatalf("EncryptAndSign failed:%s\n", err)
+	}
+	blob[len(blob)-sender.PublicKey.Size()-1] ^= 0x01 // last byte of the ciphertext
+	if _, err := VerifyAndDecrypt(recipient, &sender.PublicKey, blob); err == nil {
+		t.Errorf("Tampered ciphertext should be rejected\n")
+	}
+}
+
+func TestHybridEncryptedSize(t *testing.T) {
+	key := testPrivateKey(t)
+	for _, n := range []int{0, 1, 100, 4096} {
+		blob, err := HybridEncrypt(&key.PublicKey, make([]byte, n))
+		if err != nil {
+			t.Fatalf("HybridEncrypt failed:%s\n", err)
+		}
+		if size := HybridEncryptedSize(&key.PublicKey, n); size != len(blob) {
+			t.Errorf("Size error for %d bytes, estimate:%d, actual:%d\n", n, size, len(blob))
+		}
+	}
+	if size := HybridEncryptedSize(nil, 100); size != 0 {
+		t.Errorf("Nil key size error, is:%d, expected:0\n", size)
+	}
+}
+
+// EOF
@@ -0,0 +1,96 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// decodeJSONObject decodes a JSON object, keeping numbers as json.Number so that they are not changed
+// by a round-trip through float64. Data after the object is rejected.
+func decodeJSONObject(jsonBytes []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, errors.New("not a JSON object")
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("trailing data after the JSON object")
+	}
+	return obj, nil
+}
+
+// canonicalJSON serialises the object in a canonical form: no insignificant whitespace and the keys
+// of all objects sorted, as done by json.Marshal for maps.
+func canonicalJSON(obj map[string]interface{}) ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// SignJSONWithEmbeddedSig signs a JSON object and returns it with the base64 encoded RSA-PSS signature
+// added as the field sigField. The signature covers the canonical form of the object without sigField,
+// so the formatting of jsonBytes does not matter. Use VerifyJSONWithEmbeddedSig for verification.
+func SignJSONWithEmbeddedSig(key *rsa.PrivateKey, jsonBytes []byte, sigField string) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	obj, err := decodeJSONObject(jsonBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	delete(obj, sigField)
+	canonical, err := canonicalJSON(obj)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	sig, err := SignPSSByteArray(key, Sha256bytes2bytes(canonical))
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	obj[sigField] = base64.StdEncoding.EncodeToString(sig)
+	signed, err := canonicalJSON(obj)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return signed, nil
+}
+
+// VerifyJSONWithEmbeddedSig verifies a JSON object which contains its own signature in the field
+// sigField, as created by SignJSONWithEmbeddedSig. The field is removed, the rest of the object is
+// canonicalised and verified against the extracted signature. If no error is returned, the
+// verification was successful.
+func VerifyJSONWithEmbeddedSig(pub *rsa.PublicKey, jsonBytes []byte, sigField string) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	obj, err := decodeJSONObject(jsonBytes)
+	if err != nil {
+		return fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	sigB64, ok := obj[sigField].(string)
+	if !ok {
+		return fmt.Errorf("%s:%w:signature field %s missing or not a string", CurrentFunctionName(), ErrParse, sigField)
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return fmt.Errorf("%s:%w:decoding base64 string", CurrentFunctionName(), ErrParse)
+	}
+	delete(obj, sigField)
+	canonical, err := canonicalJSON(obj)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err := VerifyPSSByteArray(pub, sig, canonical); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// EOF
@@ -0,0 +1,42 @@
+package go_libs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONWithEmbeddedSig(t *testing.T) {
+	key := testPrivateKey(t)
+	doc := []byte(`{"order": 4711, "amount": 12345678901234567890, "items": ["a", "b"]}`)
+	signed, err := SignJSONWithEmbeddedSig(key, doc, "sig")
+	if err != nil {
+		t.Fatalf("SignJSONWithEmbeddedSig failed:%s\n", err)
+	}
+	if !strings.Contains(string(signed), "12345678901234567890") {
+		t.Errorf("Number changed by signing:%s\n", signed)
+	}
+	if err := VerifyJSONWithEmbeddedSig(&key.PublicKey, signed, "sig"); err != nil {
+		t.Errorf("VerifyJSONWithEmbeddedSig failed:%s\n", err)
+	}
+	// reformatting does not matter, the content does
+	reformatted := strings.Replace(string(signed), ",", ",\n  ", -1)
+	if err := VerifyJSONWithEmbeddedSig(&key.PublicKey, []byte(reformatted), "sig"); err != nil {
+		t.Errorf("Verification of the reformatted JSON failed:%s\n", err)
+	}
+	tampered := strings.Replace(string(signed), "4711", "4712", 1)
+	if err := VerifyJSONWithEmbeddedSig(&key.PublicKey, []byte(tampered), "sig"); !errors.Is(err, ErrVerification) {
+		t.Errorf("Tampered JSON error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	if err := VerifyJSONWithEmbeddedSig(&key.PublicKey, doc, "sig"); !errors.Is(err, ErrParse) {
+		t.Errorf("Missing signature error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	if err := VerifyJSONWithEmbeddedSig(&key.PublicKey, append(signed, "garbage"...), "sig"); !errors.Is(err, ErrParse) {
+		t.Errorf("Trailing data error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	if err := VerifyJSONWithEmbeddedSig(&key.PublicKey, append(signed, "\n"...), "sig"); err != nil {
+		t.Errorf("Verification with a trailing newline failed:%s\n", err)
+	}
+}
+
+// EOF
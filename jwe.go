@@ -0,0 +1,42 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// WrapKeyRSAOAEP encrypts a content-encryption key (CEK) for the owner of the public key and returns it
+// base64url-encoded without padding. This is the key management of the JWE algorithm RSA-OAEP-256
+// (RSA-OAEP with SHA-256 and MGF1/SHA-256), so the result can be used as the encrypted key of a JWE.
+func WrapKeyRSAOAEP(pub *rsa.PublicKey, cek []byte) (string, error) {
+	if pub == nil {
+		return "", fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(wrapped), nil
+}
+
+// UnwrapKeyRSAOAEP decrypts a content-encryption key wrapped by WrapKeyRSAOAEP (JWE RSA-OAEP-256).
+func UnwrapKeyRSAOAEP(priv *rsa.PrivateKey, wrapped string) ([]byte, error) {
+	if priv == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":Error, decoding base64url string")
+	}
+	cek, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, buf, nil)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":decryption failed, wrong key?")
+	}
+	return cek, nil
+}
+
+// EOF
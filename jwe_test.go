@@ -0,0 +1,28 @@
+package go_libs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWrapKeyRSAOAEPRoundTrip(t *testing.T) {
+	key := testPrivateKey(t)
+	cek := bytes.Repeat([]byte{0x17}, 32)
+	wrapped, err := WrapKeyRSAOAEP(&key.PublicKey, cek)
+	if err != nil {
+		t.Fatalf("WrapKeyRSAOAEP failed:%s\n", err)
+	}
+	if strings.ContainsAny(wrapped, "+/=") {
+		t.Errorf("Wrapped key is not base64url without padding:%s\n", wrapped)
+	}
+	unwrapped, err := UnwrapKeyRSAOAEP(key, wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapKeyRSAOAEP failed:%s\n", err)
+	}
+	if !bytes.Equal(unwrapped, cek) {
+		t.Errorf("Round-trip error, is:%x, expected:%x\n", unwrapped, cek)
+	}
+}
+
+// EOF
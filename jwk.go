@@ -0,0 +1,134 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// jsonWebKey is the subset of a JSON Web Key (RFC 7517) used for RSA public keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jsonWebKeySet is a JWKS document as served by OIDC providers.
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// rsaPublicKey converts the JWK to an RSA public key. n and e are big-endian, base64url-encoded
+// integers without padding.
+func (k *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, errors.New("unsupported key type " + k.Kty + ", not RSA")
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("invalid modulus n")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 {
+		return nil, errors.New("invalid exponent e")
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent e too large")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// newRSAJSONWebKey converts an RSA public key to a JWK for RS256 signatures.
+func newRSAJSONWebKey(pub *rsa.PublicKey, kid string) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+// PublicKeyToJWK returns the public key as a JSON Web Key (RFC 7517) with kty RSA, use sig, alg RS256,
+// and the key id kid. The modulus n and the exponent e are encoded as big-endian, unpadded base64url
+// integers. Several keys can be published as a JWKS in a {"keys":[...]} document.
+func PublicKeyToJWK(pub *rsa.PublicKey, kid string) ([]byte, error) {
+	if pub == nil || pub.N == nil {
+		return nil, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	jwk, err := json.Marshal(newRSAJSONWebKey(pub, kid))
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return jwk, nil
+}
+
+// PublicKeyFromJWK parses a single JSON Web Key, e.g. from PublicKeyToJWK, and returns the RSA public key
+// and its key id. Keys of other types like EC or OKP are rejected.
+func PublicKeyFromJWK(jwk []byte) (*rsa.PublicKey, string, error) {
+	var key jsonWebKey
+	if err := json.Unmarshal(jwk, &key); err != nil {
+		return nil, "", fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	if key.Kty == "" {
+		return nil, "", fmt.Errorf("%s:%w:missing key type kty, not a single JWK", CurrentFunctionName(), ErrParse)
+	}
+	pub, err := key.rsaPublicKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	return pub, key.Kid, nil
+}
+
+// CreateRSAKeyPairWithJWKS creates an RSA key-pair of the given size, see CreateRSAKeyPairBits, and
+// writes the private key as PEM to privFile and the public key as a JWKS with the single key kid to
+// jwksFile. This bootstraps a JWT signing service, e.g. an OIDC provider, in one call. Existing files
+// are not overwritten. If writing fails, files created by this call are removed again.
+func CreateRSAKeyPairWithJWKS(privFile, jwksFile string, kid string, bits int) (err error) {
+	var created []string
+	defer func() {
+		if err != nil {
+			for _, filename := range created {
+				_ = os.Remove(filename)
+			}
+		}
+	}()
+	jwksOut, err := os.OpenFile(jwksFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	created = append(created, jwksFile)
+	defer jwksOut.Close()
+	privOut, err := os.OpenFile(privFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	created = append(created, privFile)
+	defer privOut.Close()
+	privateKey, publicKey, err := CreateRSAKeyPairBits(bits)
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	jwks, err := json.MarshalIndent(jsonWebKeySet{Keys: []jsonWebKey{newRSAJSONWebKey(publicKey, kid)}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if _, err = jwksOut.Write(append(jwks, '\n')); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err = WriteRsaPrivateKey(privOut, privateKey); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return nil
+}
+
+// EOF
@@ -0,0 +1,122 @@
+package go_libs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateRSAKeyPairWithJWKS(t *testing.T) {
+	dir := t.TempDir()
+	privFile := filepath.Join(dir, "signing.pem")
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err := CreateRSAKeyPairWithJWKS(privFile, jwksFile, "key-2021", testBitSize); err != nil {
+		t.Fatalf("CreateRSAKeyPairWithJWKS failed:%s\n", err)
+	}
+	priv, err := LoadPrivateKey(privFile)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey failed:%s\n", err)
+	}
+	buf, err := os.ReadFile(jwksFile)
+	if err != nil {
+		t.Fatalf("ReadFile failed:%s\n", err)
+	}
+	var jwks jsonWebKeySet
+	if err := json.Unmarshal(buf, &jwks); err != nil {
+		t.Fatalf("Parsing the JWKS failed:%s\n", err)
+	}
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "key-2021" {
+		t.Fatalf("JWKS error, is:%s\n", buf)
+	}
+	pub, err := jwks.Keys[0].rsaPublicKey()
+	if err != nil {
+		t.Fatalf("rsaPublicKey failed:%s\n", err)
+	}
+	if !pub.Equal(&priv.PublicKey) {
+		t.Errorf("JWKS key does not match the private key\n")
+	}
+	if err := CreateRSAKeyPairWithJWKS(privFile, jwksFile, "key-2022", testBitSize); err == nil {
+		t.Errorf("Existing files should not be overwritten\n")
+	}
+	otherJWKS := filepath.Join(dir, "other.json")
+	if err := CreateRSAKeyPairWithJWKS(privFile, otherJWKS, "key-2022", testBitSize); err == nil {
+		t.Errorf("Existing private key file should not be overwritten\n")
+	}
+	if _, err := os.Stat(otherJWKS); !os.IsNotExist(err) {
+		t.Errorf("JWKS file of the failed call should be removed, stat:%v\n", err)
+	}
+	otherPriv := filepath.Join(dir, "other.pem")
+	if err := CreateRSAKeyPairWithJWKS(otherPriv, filepath.Join(dir, "other2.json"), "key-2022", 1024); err == nil {
+		t.Errorf("Weak key size should be rejected\n")
+	}
+	for _, filename := range []string{otherPriv, filepath.Join(dir, "other2.json")} {
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			t.Errorf("File %s of the failed call should be removed, stat:%v\n", filename, err)
+		}
+	}
+}
+
+func TestPublicKeyToJWK(t *testing.T) {
+	key := testPrivateKey(t)
+	buf, err := PublicKeyToJWK(&key.PublicKey, "kid-1")
+	if err != nil {
+		t.Fatalf("PublicKeyToJWK failed:%s\n", err)
+	}
+	var jwk map[string]string
+	if err := json.Unmarshal(buf, &jwk); err != nil {
+		t.Fatalf("Parsing the JWK failed:%s\n", err)
+	}
+	for field, expected := range map[string]string{"kty": "RSA", "kid": "kid-1", "alg": "RS256", "use": "sig", "e": "AQAB"} {
+		if jwk[field] != expected {
+			t.Errorf("JWK field %s error, is:%s, expected:%s\n", field, jwk[field], expected)
+		}
+	}
+	if strings.ContainsAny(jwk["n"], "+/=") {
+		t.Errorf("Modulus is not unpadded base64url, is:%s\n", jwk["n"])
+	}
+	n, err := base64.RawURLEncoding.DecodeString(jwk["n"])
+	if err != nil {
+		t.Fatalf("Decoding the modulus failed:%s\n", err)
+	}
+	if new(big.Int).SetBytes(n).Cmp(key.N) != 0 {
+		t.Errorf("Decoded modulus differs from the key\n")
+	}
+	if _, err := PublicKeyToJWK(nil, "kid"); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+func TestPublicKeyFromJWK(t *testing.T) {
+	key := testPrivateKey(t)
+	buf, err := PublicKeyToJWK(&key.PublicKey, "kid-1")
+	if err != nil {
+		t.Fatalf("PublicKeyToJWK failed:%s\n", err)
+	}
+	pub, kid, err := PublicKeyFromJWK(buf)
+	if err != nil {
+		t.Fatalf("PublicKeyFromJWK failed:%s\n", err)
+	}
+	if !pub.Equal(&key.PublicKey) || pub.E != 65537 {
+		t.Errorf("Reconstructed key differs from the original key\n")
+	}
+	if kid != "kid-1" {
+		t.Errorf("Kid error, is:%s, expected:%s\n", kid, "kid-1")
+	}
+	for name, jwk := range map[string]string{
+		"EC key":  `{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}`,
+		"JWKS":    `{"keys":[]}`,
+		"bad n":   `{"kty":"RSA","n":"!!","e":"AQAB"}`,
+		"no JSON": `kty=RSA`,
+	} {
+		if _, _, err := PublicKeyFromJWK([]byte(jwk)); !errors.Is(err, ErrParse) {
+			t.Errorf("Error for %s, is:%v, expected:%v\n", name, err, ErrParse)
+		}
+	}
+}
+
+// EOF
@@ -0,0 +1,201 @@
+package go_libs
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultJWKSCacheTTL defines how long a fetched JWKS is reused by VerifyJWTFromJWKSURL.
+const defaultJWKSCacheTTL = 5 * time.Minute
+
+// jwksMinRefreshInterval is the minimum time between two fetches of a JWKSCache triggered by unknown
+// kids. Tokens with random kids can therefore not be used to flood the endpoint of the issuer.
+const jwksMinRefreshInterval = 30 * time.Second
+
+// maxJWKSSize is the maximum size of a fetched JWKS document. Real key sets are a few KiB.
+const maxJWKSSize = 1024 * 1024
+
+// JWKSCache fetches the JWKS from a URL and caches it for the TTL. If the TTL expired or a JWT refers to
+// a kid not contained in the cached set, e.g. after a key rotation, the JWKS is fetched again. Unknown
+// kids trigger at most one fetch every 30 seconds and concurrent lookups share a running fetch. This
+// prevents hammering the endpoint of the issuer. A JWKSCache is safe for concurrent use.
+type JWKSCache struct {
+	url         string
+	ttl         time.Duration
+	mutex       sync.Mutex
+	keys        jsonWebKeySet
+	fetched     time.Time    // time of the last successful fetch
+	lastRefresh time.Time    // start of the last fetch, successful or not
+	refreshing  *jwksRefresh // running fetch, nil if none
+}
+
+// jwksRefresh is a running fetch of a JWKSCache. done is closed when the fetch finished, err is only
+// valid afterwards.
+type jwksRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKSCache creates a cache for the JWKS served at the URL. Nothing is fetched until the first lookup.
+func NewJWKSCache(url string, ttl time.Duration) *JWKSCache {
+	return &JWKSCache{url: url, ttl: ttl}
+}
+
+var jwksCachesMutex sync.Mutex
+var jwksCaches = map[string]*JWKSCache{} // caches used by VerifyJWTFromJWKSURL, by URL
+
+// fetchJWKS downloads and parses the JWKS from the URL. Documents larger than maxJWKSSize are rejected.
+func fetchJWKS(ctx context.Context, url string) (jsonWebKeySet, error) {
+	var jwks jsonWebKeySet
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return jwks, errors.New("creating request:" + err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return jwks, errors.New("fetching JWKS:" + err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return jwks, errors.New("fetching JWKS: HTTP status " + strconv.Itoa(resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize+1))
+	if err != nil {
+		return jwks, errors.New("reading JWKS:" + err.Error())
+	}
+	if len(body) > maxJWKSSize {
+		return jwks, errors.New("reading JWKS: document exceeds " + strconv.Itoa(maxJWKSSize) + " bytes")
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return jwks, errors.New("parsing JWKS:" + err.Error())
+	}
+	return jwks, nil
+}
+
+// refresh fetches the JWKS and stores it in the cache. If a fetch is already running, its result is
+// awaited instead. The mutex is not held during the fetch, the caller must not hold it.
+func (c *JWKSCache) refresh(ctx context.Context) error {
+	c.mutex.Lock()
+	if running := c.refreshing; running != nil {
+		c.mutex.Unlock()
+		select {
+		case <-running.done:
+			return running.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	running := &jwksRefresh{done: make(chan struct{})}
+	c.refreshing = running
+	c.lastRefresh = time.Now()
+	c.mutex.Unlock()
+
+	jwks, err := fetchJWKS(ctx, c.url)
+
+	c.mutex.Lock()
+	if err == nil {
+		c.keys = jwks
+		c.fetched = time.Now()
+	}
+	c.refreshing = nil
+	c.mutex.Unlock()
+	running.err = err
+	close(running.done)
+	return err
+}
+
+// cached returns the cached JWKS and whether it is expired.
+func (c *JWKSCache) cached() (jsonWebKeySet, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.keys, c.fetched.IsZero() || time.Since(c.fetched) >= c.ttl
+}
+
+// mayRefreshForKid returns true if the last fetch is long enough ago to fetch again for an unknown kid.
+func (c *JWKSCache) mayRefreshForKid() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return time.Since(c.lastRefresh) >= jwksMinRefreshInterval
+}
+
+// PublicKey returns the RSA public key with the kid. The cached JWKS is refreshed if it is expired or,
+// at most every 30 seconds, if it does not contain the kid.
+func (c *JWKSCache) PublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	keys, expired := c.cached()
+	if expired {
+		if err := c.refresh(ctx); err != nil {
+			return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+		}
+		keys, _ = c.cached()
+	}
+	pub, err := keys.keyByKid(kid)
+	if err != nil && !expired && c.mayRefreshForKid() {
+		if err := c.refresh(ctx); err != nil {
+			return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+		}
+		keys, _ = c.cached()
+		pub, err = keys.keyByKid(kid)
+	}
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return pub, nil
+}
+
+// VerifyJWT verifies the JWT with the key selected by the kid of its header, see VerifyJWT.
+func (c *JWKSCache) VerifyJWT(ctx context.Context, token string) (map[string]interface{}, error) {
+	jwt, err := parseJWT(token)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	pub, err := c.PublicKey(ctx, jwt.header.Kid)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	claims, err := VerifyJWT(pub, token)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return claims, nil
+}
+
+// keyByKid selects the RSA key with the kid from the JWKS. If kid is empty and the set contains
+// exactly one key, this key is used.
+func (jwks *jsonWebKeySet) keyByKid(kid string) (*rsa.PublicKey, error) {
+	if kid == "" && len(jwks.Keys) == 1 {
+		return jwks.Keys[0].rsaPublicKey()
+	}
+	for i := range jwks.Keys {
+		if jwks.Keys[i].Kid == kid {
+			return jwks.Keys[i].rsaPublicKey()
+		}
+	}
+	return nil, errors.New("no key found for kid " + kid)
+}
+
+// VerifyJWTFromJWKSURL verifies an RSA-signed JWT with a public key from the JWKS served at the URL, as
+// published by OIDC providers. The key is selected by the kid of the JWT header. The JWKS is fetched
+// with the context and cached for a few minutes, see JWKSCache. On success, the claims are returned.
+func VerifyJWTFromJWKSURL(ctx context.Context, url, token string) (map[string]interface{}, error) {
+	jwksCachesMutex.Lock()
+	cache, ok := jwksCaches[url]
+	if !ok {
+		cache = NewJWKSCache(url, defaultJWKSCacheTTL)
+		jwksCaches[url] = cache
+	}
+	jwksCachesMutex.Unlock()
+	claims, err := cache.VerifyJWT(ctx, token)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return claims, nil
+}
+
+// EOF
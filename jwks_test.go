@@ -0,0 +1,150 @@
+package go_libs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testJWKS returns a JWKS document containing the public key with the kid.
+func testJWKS(t *testing.T, pub *rsa.PublicKey, kid string) []byte {
+	jwks := jsonWebKeySet{Keys: []jsonWebKey{{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}
+	buf, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("Marshal of JWKS failed:%s\n", err)
+	}
+	return buf
+}
+
+func TestVerifyJWTFromJWKSURL(t *testing.T) {
+	key := testPrivateKey(t)
+	jwks := testJWKS(t, &key.PublicKey, "kid-1")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwks)
+	}))
+	defer server.Close()
+
+	token := signTestJWT(t, key, "kid-1", map[string]interface{}{"sub": "bob"})
+	claims, err := VerifyJWTFromJWKSURL(context.Background(), server.URL, token)
+	if err != nil {
+		t.Fatalf("VerifyJWTFromJWKSURL failed:%s\n", err)
+	}
+	if claims["sub"] != "bob" {
+		t.Errorf("Claim error, is:%v, expected:bob\n", claims["sub"])
+	}
+	unknown := signTestJWT(t, key, "kid-2", map[string]interface{}{"sub": "bob"})
+	if _, err := VerifyJWTFromJWKSURL(context.Background(), server.URL, unknown); err == nil {
+		t.Errorf("Token with unknown kid should be rejected\n")
+	}
+}
+
+func TestVerifyJWTFromJWKSURLHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	token := signTestJWT(t, testPrivateKey(t), "kid-1", map[string]interface{}{})
+	if _, err := VerifyJWTFromJWKSURL(context.Background(), server.URL, token); err == nil {
+		t.Errorf("HTTP error should be reported\n")
+	}
+}
+
+func TestFetchJWKSSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys":[],"padding":"`))
+		_, _ = w.Write(bytes.Repeat([]byte("x"), maxJWKSSize))
+		_, _ = w.Write([]byte(`"}`))
+	}))
+	defer server.Close()
+
+	if _, err := fetchJWKS(context.Background(), server.URL); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Oversized JWKS error, is:%v, expected an error about the size\n", err)
+	}
+}
+
+func TestJWKSCacheTTL(t *testing.T) {
+	key := testPrivateKey(t)
+	jwks := testJWKS(t, &key.PublicKey, "kid-1")
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write(jwks)
+	}))
+	defer server.Close()
+
+	cache := NewJWKSCache(server.URL, time.Hour)
+	token := signTestJWT(t, key, "kid-1", map[string]interface{}{"sub": "carol"})
+	for i := 0; i < 2; i++ {
+		if _, err := cache.VerifyJWT(context.Background(), token); err != nil {
+			t.Fatalf("VerifyJWT %d failed:%s\n", i, err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("Fetch count error, is:%d, expected:1\n", n)
+	}
+	unknown := signTestJWT(t, key, "kid-2", map[string]interface{}{})
+	if _, err := cache.VerifyJWT(context.Background(), unknown); err == nil {
+		t.Errorf("Token with unknown kid should be rejected\n")
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("Unknown kid right after a fetch should not trigger a refetch, fetch count:%d\n", n)
+	}
+	cache.lastRefresh = time.Now().Add(-jwksMinRefreshInterval)
+	for i := 0; i < 3; i++ {
+		if _, err := cache.VerifyJWT(context.Background(), unknown); err == nil {
+			t.Errorf("Token with unknown kid should be rejected\n")
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Errorf("Unknown kids should trigger exactly one refetch per interval, fetch count:%d\n", n)
+	}
+}
+
+func TestJWKSCacheConcurrentRefresh(t *testing.T) {
+	key := testPrivateKey(t)
+	jwks := testJWKS(t, &key.PublicKey, "kid-1")
+	var fetches int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		_, _ = w.Write(jwks)
+	}))
+	defer server.Close()
+
+	cache := NewJWKSCache(server.URL, time.Hour)
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := cache.PublicKey(context.Background(), "kid-1")
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // let the lookups wait for the running fetch
+	close(release)
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("PublicKey failed:%s\n", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("Concurrent lookups should share one fetch, fetch count:%d\n", n)
+	}
+}
+
+// EOF
@@ -0,0 +1,257 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// jwtHeader contains the JOSE header fields of a JWT evaluated by this package.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// parsedJWT is a JWT split into its parts. The signature is not verified yet.
+type parsedJWT struct {
+	header       jwtHeader
+	claims       map[string]interface{}
+	signingInput string // header.payload, the part covered by the signature
+	signature    []byte
+}
+
+// parseJWT splits a compact serialised JWT and decodes header, claims, and signature. It does not
+// verify the signature. Numeric claims are decoded as json.Number, so large values keep their precision.
+func parseJWT(token string) (*parsedJWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w:token must consist of 3 parts", ErrParse)
+	}
+	var jwt parsedJWT
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w:decoding header:%s", ErrParse, err.Error())
+	}
+	if err := json.Unmarshal(headerJSON, &jwt.header); err != nil {
+		return nil, fmt.Errorf("%w:parsing header:%s", ErrParse, err.Error())
+	}
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w:decoding claims:%s", ErrParse, err.Error())
+	}
+	dec := json.NewDecoder(bytes.NewReader(claimsJSON))
+	dec.UseNumber()
+	if err := dec.Decode(&jwt.claims); err != nil {
+		return nil, fmt.Errorf("%w:parsing claims:%s", ErrParse, err.Error())
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w:parsing claims:trailing data", ErrParse)
+	}
+	if jwt.signature, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("%w:decoding signature:%s", ErrParse, err.Error())
+	}
+	jwt.signingInput = parts[0] + "." + parts[1]
+	return &jwt, nil
+}
+
+// verifySignature checks the JWT signature with the public key. RS256/384/512 and PS256/384/512 are supported.
+func (jwt *parsedJWT) verifySignature(pub *rsa.PublicKey) error {
+	if len(jwt.header.Alg) != 5 {
+		return fmt.Errorf("%w:unsupported alg %s", ErrParse, jwt.header.Alg)
+	}
+	hash, err := hashByName("SHA" + jwt.header.Alg[2:])
+	if err != nil {
+		return fmt.Errorf("%w:unsupported alg %s", ErrParse, jwt.header.Alg)
+	}
+	h := hash.New()
+	h.Write([]byte(jwt.signingInput))
+	digest := h.Sum(nil)
+	switch jwt.header.Alg[:2] {
+	case "RS":
+		return rsa.VerifyPKCS1v15(pub, hash, digest, jwt.signature)
+	case "PS":
+		return rsa.VerifyPSS(pub, hash, digest, jwt.signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	default:
+		return fmt.Errorf("%w:unsupported alg %s", ErrParse, jwt.header.Alg)
+	}
+}
+
+// signJWT creates a compact serialised JWT with the header and the claims. The alg of the header
+// selects the algorithm, RS256/384/512 or PS256/384/512.
+func signJWT(key *rsa.PrivateKey, header jwtHeader, claims map[string]interface{}) (string, error) {
+	if len(header.Alg) != 5 {
+		return "", errors.New("unsupported alg " + header.Alg)
+	}
+	hash, err := hashByName("SHA" + header.Alg[2:])
+	if err != nil {
+		return "", errors.New("unsupported alg " + header.Alg)
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	var sig []byte
+	switch header.Alg[:2] {
+	case "RS":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, hash, h.Sum(nil))
+	case "PS":
+		sig, err = rsa.SignPSS(rand.Reader, key, hash, h.Sum(nil), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	default:
+		return "", errors.New("unsupported alg " + header.Alg)
+	}
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// numericDate returns the claim as seconds since the epoch (NumericDate of RFC 7519). ok is false if the
+// claim is missing. Fractional seconds are truncated.
+func (jwt *parsedJWT) numericDate(name string) (seconds int64, ok bool, err error) {
+	value, present := jwt.claims[name]
+	if !present {
+		return 0, false, nil
+	}
+	number, isNumber := value.(json.Number)
+	if !isNumber {
+		return 0, false, fmt.Errorf("%w:claim %s is not numeric", ErrParse, name)
+	}
+	if seconds, err := number.Int64(); err == nil {
+		return seconds, true, nil
+	}
+	f, err := number.Float64()
+	if err != nil || f >= math.MaxInt64 || f <= math.MinInt64 {
+		return 0, false, fmt.Errorf("%w:claim %s is out of range", ErrParse, name)
+	}
+	return int64(f), true, nil
+}
+
+// checkNotBefore validates the nbf claim, if present, against the current time.
+func (jwt *parsedJWT) checkNotBefore(now time.Time) error {
+	nbf, ok, err := jwt.numericDate("nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Unix() < nbf {
+		return fmt.Errorf("%w:token not valid yet", ErrVerification)
+	}
+	return nil
+}
+
+// checkTimes validates the exp and nbf claims, if present, against the current time.
+func (jwt *parsedJWT) checkTimes(now time.Time) error {
+	exp, ok, err := jwt.numericDate("exp")
+	if err != nil {
+		return err
+	}
+	if ok && now.Unix() >= exp {
+		return fmt.Errorf("%w:token expired", ErrVerification)
+	}
+	return jwt.checkNotBefore(now)
+}
+
+// VerifyJWT verifies the signature of an RSA-signed JWT (RS256/384/512, PS256/384/512) and checks the
+// exp and nbf claims. On success, the claims are returned; numeric claims as json.Number.
+func VerifyJWT(pub *rsa.PublicKey, token string) (map[string]interface{}, error) {
+	if pub == nil {
+		return nil, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	jwt, err := parseJWT(token)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := jwt.verifySignature(pub); err != nil {
+		return nil, fmt.Errorf("%s:signature verification:%w", CurrentFunctionName(), err)
+	}
+	if err := jwt.checkTimes(time.Now()); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return jwt.claims, nil
+}
+
+// JWTRemainingTTL returns the time until the exp claim of the JWT is reached, so clients can refresh a
+// token before it expires. The signature is NOT verified. The result is negative for expired tokens.
+func JWTRemainingTTL(token string) (time.Duration, error) {
+	jwt, err := parseJWT(token)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	exp, ok, err := jwt.numericDate("exp")
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if !ok {
+		return 0, fmt.Errorf("%s:%w:token has no numeric exp claim", CurrentFunctionName(), ErrParse)
+	}
+	return time.Until(time.Unix(exp, 0)), nil
+}
+
+// SignJWT creates an RS256-signed JWT with the claims. If kid is not empty, it is added to the header,
+// so verifiers can select the key from a JWKS, see VerifyJWTFromJWKSURL.
+func SignJWT(key *rsa.PrivateKey, claims map[string]interface{}, kid string) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	token, err := signJWT(key, jwtHeader{Alg: "RS256", Kid: kid, Typ: "JWT"}, claims)
+	if err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return token, nil
+}
+
+// jwtRefreshGrace defines how long after expiry a token can still be refreshed by RefreshJWT.
+const jwtRefreshGrace = 5 * time.Minute
+
+// RefreshJWT verifies the signature of the old token with pub, extends its exp claim by extend, and
+// signs it again with key using the same alg and kid. Tokens which expired more than a short grace
+// period ago or which are not valid yet (nbf) are rejected. All other claims are kept.
+func RefreshJWT(pub *rsa.PublicKey, key *rsa.PrivateKey, oldToken string, extend time.Duration) (string, error) {
+	if pub == nil || key == nil {
+		return "", fmt.Errorf("%s:Error, %w", CurrentFunctionName(), ErrNilKey)
+	}
+	jwt, err := parseJWT(oldToken)
+	if err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := jwt.verifySignature(pub); err != nil {
+		return "", fmt.Errorf("%s:signature verification:%w", CurrentFunctionName(), err)
+	}
+	now := time.Now()
+	if err := jwt.checkNotBefore(now); err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	exp, ok, err := jwt.numericDate("exp")
+	if err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if !ok {
+		return "", fmt.Errorf("%s:%w:token has no numeric exp claim", CurrentFunctionName(), ErrParse)
+	}
+	if now.After(time.Unix(exp, 0).Add(jwtRefreshGrace)) {
+		return "", fmt.Errorf("%s:%w:token expired, too late for refresh", CurrentFunctionName(), ErrVerification)
+	}
+	jwt.claims["exp"] = exp + int64(extend/time.Second)
+	token, err := signJWT(key, jwt.header, jwt.claims)
+	if err != nil {
+		return "", fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return token, nil
+}
+
+// EOF
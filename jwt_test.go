@@ -0,0 +1,110 @@
+package go_libs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// signTestJWT creates an RS256 JWT over the claims.
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(jwtHeader{Alg: "RS256", Kid: kid, Typ: "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Marshal of claims failed:%s\n", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15 failed:%s\n", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyJWT(t *testing.T) {
+	key := testPrivateKey(t)
+	token := signTestJWT(t, key, "", map[string]interface{}{"sub": "alice", "exp": time.Now().Add(time.Minute).Unix()})
+	claims, err := VerifyJWT(&key.PublicKey, token)
+	if err != nil {
+		t.Fatalf("VerifyJWT failed:%s\n", err)
+	}
+	if claims["sub"] != "alice" {
+		t.Errorf("Claim error, is:%v, expected:alice\n", claims["sub"])
+	}
+	expired := signTestJWT(t, key, "", map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})
+	if _, err := VerifyJWT(&key.PublicKey, expired); err == nil {
+		t.Errorf("Expired token should be rejected\n")
+	}
+}
+
+func TestJWTRemainingTTL(t *testing.T) {
+	token := signTestJWT(t, testPrivateKey(t), "", map[string]interface{}{"exp": time.Now().Add(60 * time.Second).Unix()})
+	ttl, err := JWTRemainingTTL(token)
+	if err != nil {
+		t.Fatalf("JWTRemainingTTL failed:%s\n", err)
+	}
+	if ttl <= 58*time.Second || ttl > 60*time.Second {
+		t.Errorf("TTL error, is:%s, expected:about 60s\n", ttl)
+	}
+	noExp := signTestJWT(t, testPrivateKey(t), "", map[string]interface{}{"sub": "alice"})
+	if _, err := JWTRemainingTTL(noExp); err == nil {
+		t.Errorf("Token without exp should be rejected\n")
+	}
+}
+
+func TestRefreshJWT(t *testing.T) {
+	key := testPrivateKey(t)
+	exp := time.Now().Add(time.Minute).Unix()
+	token, err := SignJWT(key, map[string]interface{}{"sub": "alice", "exp": exp}, "kid-1")
+	if err != nil {
+		t.Fatalf("SignJWT failed:%s\n", err)
+	}
+	refreshed, err := RefreshJWT(&key.PublicKey, key, token, time.Hour)
+	if err != nil {
+		t.Fatalf("RefreshJWT failed:%s\n", err)
+	}
+	claims, err := VerifyJWT(&key.PublicKey, refreshed)
+	if err != nil {
+		t.Fatalf("VerifyJWT of the refreshed token failed:%s\n", err)
+	}
+	if newExp, _ := claims["exp"].(json.Number).Int64(); newExp != exp+3600 {
+		t.Errorf("exp error, is:%d, expected:%d\n", newExp, exp+3600)
+	}
+	if claims["sub"] != "alice" {
+		t.Errorf("Claim error, is:%v, expected:alice\n", claims["sub"])
+	}
+	expired := signTestJWT(t, key, "", map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})
+	if _, err := RefreshJWT(&key.PublicKey, key, expired, time.Hour); err == nil {
+		t.Errorf("Refresh of a long expired token should be rejected\n")
+	}
+	notYet := signTestJWT(t, key, "", map[string]interface{}{"exp": exp, "nbf": time.Now().Add(time.Hour).Unix()})
+	if _, err := RefreshJWT(&key.PublicKey, key, notYet, time.Hour); !errors.Is(err, ErrVerification) {
+		t.Errorf("Refresh of a not yet valid token error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+}
+
+func TestJWTNumericClaims(t *testing.T) {
+	key := testPrivateKey(t)
+	const id = int64(1<<53 + 1) // not representable as float64
+	token := signTestJWT(t, key, "", map[string]interface{}{"id": id, "exp": time.Now().Add(time.Minute).Unix()})
+	claims, err := VerifyJWT(&key.PublicKey, token)
+	if err != nil {
+		t.Fatalf("VerifyJWT failed:%s\n", err)
+	}
+	if got, _ := claims["id"].(json.Number).Int64(); got != id {
+		t.Errorf("Numeric claim error, is:%d, expected:%d\n", got, id)
+	}
+	stringExp := signTestJWT(t, key, "", map[string]interface{}{"exp": "never"})
+	if _, err := VerifyJWT(&key.PublicKey, stringExp); !errors.Is(err, ErrParse) {
+		t.Errorf("Non-numeric exp error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+// EOF
@@ -0,0 +1,31 @@
+package go_libs
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// DeriveKeyPBKDF2 derives a key of keyLen bytes from the password with PBKDF2 (RFC 8018) and
+// HMAC-SHA256, e.g. the 32-byte key of EncryptAES256. Use a random salt of at least 16 bytes, see
+// GenerateSalt, and store it next to the ciphertext. OWASP recommends at least 600000 iterations for
+// PBKDF2-HMAC-SHA256, which is also used by WriteEncryptedPrivateKey. nil is returned if the number of
+// iterations or the key length is not positive.
+func DeriveKeyPBKDF2(password, salt []byte, iterations, keyLen int) []byte {
+	if iterations <= 0 || keyLen <= 0 {
+		return nil
+	}
+	return pbkdf2.Key(password, salt, iterations, keyLen, sha256.New)
+}
+
+// GenerateSalt returns n random bytes read from crypto/rand, e.g. as the salt of DeriveKeyPBKDF2.
+func GenerateSalt(n int) ([]byte, error) {
+	salt, err := randomBytes(n)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return salt, nil
+}
+
+// EOF
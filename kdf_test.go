@@ -0,0 +1,42 @@
+package go_libs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDeriveKeyPBKDF2(t *testing.T) {
+	// test vector of RFC 7914, section 11, for PBKDF2-HMAC-SHA256
+	key := DeriveKeyPBKDF2([]byte("passwd"), []byte("salt"), 1, 64)
+	expected := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("Derived key error, is:%x, expected:%s\n", key, expected)
+	}
+	if DeriveKeyPBKDF2([]byte("passwd"), []byte("salt"), 1, 0) != nil {
+		t.Errorf("Key length 0 should be rejected\n")
+	}
+	if DeriveKeyPBKDF2([]byte("passwd"), []byte("salt"), 0, 32) != nil {
+		t.Errorf("0 iterations should be rejected\n")
+	}
+}
+
+func TestGenerateSalt(t *testing.T) {
+	salt1, err := GenerateSalt(16)
+	if err != nil {
+		t.Fatalf("GenerateSalt failed:%s\n", err)
+	}
+	salt2, err := GenerateSalt(16)
+	if err != nil {
+		t.Fatalf("GenerateSalt failed:%s\n", err)
+	}
+	if len(salt1) != 16 || bytes.Equal(salt1, salt2) {
+		t.Errorf("Salt error, is:%x and %x\n", salt1, salt2)
+	}
+	if _, err := GenerateSalt(0); err == nil {
+		t.Errorf("Salt size 0 should be rejected\n")
+	}
+}
+
+// EOF
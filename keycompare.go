@@ -0,0 +1,90 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"os"
+)
+
+// publicKeyFromPem returns the public key of a PEM buffer containing either a private or a public key.
+func publicKeyFromPem(buf []byte) (*rsa.PublicKey, error) {
+	if priv, err := Pem2RsaPrivateKey(buf); err == nil {
+		return &priv.PublicKey, nil
+	}
+	return Pem2RsaPublicKey(buf)
+}
+
+// loadPublicKeyFromKeyFile loads a private or public PEM key file and returns the public key.
+func loadPublicKeyFromKeyFile(filename string) (*rsa.PublicKey, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.New("reading file:" + err.Error())
+	}
+	pub, err := publicKeyFromPem(buf)
+	if err != nil {
+		return nil, errors.New(filename + ":" + err.Error())
+	}
+	return pub, nil
+}
+
+// SameKeyFile loads 2 key files, each containing a private or a public key, and checks if they belong
+// to the same key by comparing their public components. So, a private key file and its .pub file are
+// also reported as the same key.
+func SameKeyFile(fileA, fileB string) (bool, error) {
+	pubA, err := loadPublicKeyFromKeyFile(fileA)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	pubB, err := loadPublicKeyFromKeyFile(fileB)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return pubA.Equal(pubB), nil
+}
+
+// PEMFilesEqual loads 2 PEM key files and checks if they contain the same key. The keys are compared,
+// not the bytes of the files, so PEM reformatting or a different encoding like PKCS#1 vs PKCS#8 does
+// not count as a change. A private key and a public key are never equal, use SameKeyFile to check if
+// they belong together.
+func PEMFilesEqual(a, b string) (bool, error) {
+	bufA, err := os.ReadFile(a)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
+	}
+	bufB, err := os.ReadFile(b)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
+	}
+	privA, errA := Pem2RsaPrivateKey(bufA)
+	privB, errB := Pem2RsaPrivateKey(bufB)
+	switch {
+	case errA == nil && errB == nil:
+		return privA.Equal(privB), nil
+	case errA == nil || errB == nil:
+		// one private key, one public key or garbage
+		if _, err := publicKeyFromPem(bufA); err != nil {
+			return false, errors.New(CurrentFunctionName() + ":" + a + ":" + err.Error())
+		}
+		if _, err := publicKeyFromPem(bufB); err != nil {
+			return false, errors.New(CurrentFunctionName() + ":" + b + ":" + err.Error())
+		}
+		return false, nil
+	}
+	pubA, err := Pem2RsaPublicKey(bufA)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":" + a + ":" + err.Error())
+	}
+	pubB, err := Pem2RsaPublicKey(bufB)
+	if err != nil {
+		return false, errors.New(CurrentFunctionName() + ":" + b + ":" + err.Error())
+	}
+	return pubA.Equal(pubB), nil
+}
+
+// KeysArePair checks if the public key belongs to the private key, e.g. after loading them from separate
+// files. This catches mismatched key files before signing or verifying. nil keys are never a pair.
+func KeysArePair(priv *rsa.PrivateKey, pub *rsa.PublicKey) bool {
+	return priv != nil && pub != nil && priv.PublicKey.Equal(pub)
+}
+
+// EOF
@@ -0,0 +1,109 @@
+package go_libs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestKeyPair writes the key to basename and basename.pub in the directory.
+func writeTestKeyPair(t *testing.T, dir string, basename string, kp *KeyPair) string {
+	filename := filepath.Join(dir, basename)
+	if err := kp.WriteFiles(filename); err != nil {
+		t.Fatalf("WriteFiles failed:%s\n", err)
+	}
+	return filename
+}
+
+func TestSameKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	kp, _ := KeyPairFromPrivateKey(testPrivateKey(t))
+	other, _ := KeyPairFromPrivateKey(newTestPrivateKey(t))
+	keyFile := writeTestKeyPair(t, dir, "key", kp)
+	otherFile := writeTestKeyPair(t, dir, "other", other)
+
+	buf, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatalf("ReadFile failed:%s\n", err)
+	}
+	copyFile := filepath.Join(dir, "copy")
+	if err := os.WriteFile(copyFile, buf, 0600); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+
+	for _, tc := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{keyFile, copyFile, true},
+		{keyFile, keyFile + ".pub", true},
+		{keyFile, otherFile, false},
+		{keyFile + ".pub", otherFile + ".pub", false},
+	} {
+		same, err := SameKeyFile(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("SameKeyFile failed:%s\n", err)
+		}
+		if same != tc.expected {
+			t.Errorf("SameKeyFile(%s, %s) error, is:%t, expected:%t\n", tc.a, tc.b, same, tc.expected)
+		}
+	}
+}
+
+func TestPEMFilesEqual(t *testing.T) {
+	dir := t.TempDir()
+	key := testPrivateKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey failed:%s\n", err)
+	}
+	pkcs1File := filepath.Join(dir, "pkcs1.pem")
+	pkcs8File := filepath.Join(dir, "pkcs8.pem")
+	if err := os.WriteFile(pkcs1File, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	if err := os.WriteFile(pkcs8File, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0600); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	kp, _ := KeyPairFromPrivateKey(key)
+	other, _ := KeyPairFromPrivateKey(newTestPrivateKey(t))
+	keyFile := writeTestKeyPair(t, dir, "key", kp)
+	otherFile := writeTestKeyPair(t, dir, "other", other)
+
+	for _, tc := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{pkcs1File, pkcs8File, true},
+		{keyFile + ".pub", keyFile + ".pub", true},
+		{pkcs8File, otherFile, false},
+		{keyFile, keyFile + ".pub", false},
+		{keyFile + ".pub", otherFile + ".pub", false},
+	} {
+		equal, err := PEMFilesEqual(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("PEMFilesEqual failed:%s\n", err)
+		}
+		if equal != tc.expected {
+			t.Errorf("PEMFilesEqual(%s, %s) error, is:%t, expected:%t\n", tc.a, tc.b, equal, tc.expected)
+		}
+	}
+}
+
+func TestKeysArePair(t *testing.T) {
+	key := testPrivateKey(t)
+	other := newTestPrivateKey(t)
+	if !KeysArePair(key, &key.PublicKey) {
+		t.Errorf("Matching keys are not reported as a pair\n")
+	}
+	if KeysArePair(key, &other.PublicKey) {
+		t.Errorf("Mismatched keys are reported as a pair\n")
+	}
+	if KeysArePair(nil, &key.PublicKey) || KeysArePair(key, nil) {
+		t.Errorf("nil keys are reported as a pair\n")
+	}
+}
+
+// EOF
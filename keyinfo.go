@@ -0,0 +1,82 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// KeyInfo describes a key for diagnostic output, e.g. of a key inspection tool.
+type KeyInfo struct {
+	Type        string // private or public
+	Algorithm   string // RSA
+	Bits        int    // size of the modulus
+	Fingerprint string // see PublicKeyFingerprint
+	Exponent    int    // public exponent
+}
+
+// String returns a human-readable, single-line description of the key.
+func (info KeyInfo) String() string {
+	return fmt.Sprintf("%s %s key, %d bits, exponent %d, fingerprint %s",
+		info.Algorithm, info.Type, info.Bits, info.Exponent, info.Fingerprint)
+}
+
+// InspectKey parses the first PEM block, a private or a public RSA key, and returns its KeyInfo.
+func InspectKey(pemBytes []byte) (KeyInfo, error) {
+	info := KeyInfo{Algorithm: "RSA", Type: "public"}
+	var pub *rsa.PublicKey
+	if priv, err := Pem2RsaPrivateKey(pemBytes); err == nil {
+		info.Type = "private"
+		pub = &priv.PublicKey
+	} else if pub, err = Pem2RsaPublicKey(pemBytes); err != nil {
+		return KeyInfo{}, fmt.Errorf("%s:%w:no RSA private or public key found", CurrentFunctionName(), ErrParse)
+	}
+	fingerprint, err := PublicKeyFingerprint(pub)
+	if err != nil {
+		return KeyInfo{}, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	info.Bits = pub.N.BitLen()
+	info.Exponent = pub.E
+	info.Fingerprint = fingerprint
+	return info, nil
+}
+
+// CheckKeyStrength returns an error wrapping ErrWeakKey if the modulus of the key has less than minBits
+// bits, e.g. 2048. Use it for keys from untrusted sources, a 512-bit key can be factored easily.
+func CheckKeyStrength(pub *rsa.PublicKey, minBits int) error {
+	if pub == nil || pub.N == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if bits := pub.N.BitLen(); bits < minBits {
+		return fmt.Errorf("%s:%w:key size %d bits, minimum is %d bits", CurrentFunctionName(), ErrWeakKey, bits, minBits)
+	}
+	return nil
+}
+
+// ParsePrivateKeyMinBits works like Pem2RsaPrivateKey, but rejects keys smaller than minBits, see
+// CheckKeyStrength.
+func ParsePrivateKeyMinBits(der []byte, minBits int) (*rsa.PrivateKey, error) {
+	priv, err := Pem2RsaPrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := CheckKeyStrength(&priv.PublicKey, minBits); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return priv, nil
+}
+
+// ParsePublicKeyMinBits works like Pem2RsaPublicKey, but rejects keys smaller than minBits, see
+// CheckKeyStrength.
+func ParsePublicKeyMinBits(der []byte, minBits int) (*rsa.PublicKey, error) {
+	pub, err := Pem2RsaPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := CheckKeyStrength(pub, minBits); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return pub, nil
+}
+
+// EOF
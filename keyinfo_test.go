@@ -0,0 +1,65 @@
+package go_libs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestInspectKeyPublic(t *testing.T) {
+	key := testPrivateKey(t)
+	var buf bytes.Buffer
+	if err := WritePublicKeyWithComment(&buf, &key.PublicKey, "sample"); err != nil {
+		t.Fatalf("WritePublicKeyWithComment failed:%s\n", err)
+	}
+	info, err := InspectKey(buf.Bytes())
+	if err != nil {
+		t.Fatalf("InspectKey failed:%s\n", err)
+	}
+	fingerprint, _ := PublicKeyFingerprint(&key.PublicKey)
+	expected := KeyInfo{Type: "public", Algorithm: "RSA", Bits: testBitSize, Fingerprint: fingerprint, Exponent: 65537}
+	if info != expected {
+		t.Errorf("KeyInfo error, is:%s, expected:%s\n", info, expected)
+	}
+	if _, err := InspectKey([]byte("garbage")); err == nil {
+		t.Errorf("InspectKey should fail for non-PEM input\n")
+	}
+}
+
+func TestKeyMinBits(t *testing.T) {
+	key := testPrivateKey(t)
+	privPEM, err := PrivateKeyToPEMString(key)
+	if err != nil {
+		t.Fatalf("PrivateKeyToPEMString failed:%s\n", err)
+	}
+	pubPEM, err := PublicKeyToPEMString(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("PublicKeyToPEMString failed:%s\n", err)
+	}
+	if err := CheckKeyStrength(&key.PublicKey, testBitSize); err != nil {
+		t.Errorf("CheckKeyStrength failed:%s\n", err)
+	}
+	if err := CheckKeyStrength(&key.PublicKey, testBitSize+1); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("Weak key error, is:%v, expected:%v\n", err, ErrWeakKey)
+	}
+	if _, err := ParsePrivateKeyMinBits([]byte(privPEM), testBitSize); err != nil {
+		t.Errorf("ParsePrivateKeyMinBits failed:%s\n", err)
+	}
+	if _, err := ParsePrivateKeyMinBits([]byte(privPEM), 4096); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("Weak private key error, is:%v, expected:%v\n", err, ErrWeakKey)
+	}
+	if _, err := ParsePublicKeyMinBits([]byte(pubPEM), testBitSize); err != nil {
+		t.Errorf("ParsePublicKeyMinBits failed:%s\n", err)
+	}
+	if _, err := ParsePublicKeyMinBits([]byte(pubPEM), 4096); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("Weak public key error, is:%v, expected:%v\n", err, ErrWeakKey)
+	}
+	if _, err := ParsePublicKeyMinBits([]byte("garbage"), 2048); !errors.Is(err, ErrParse) {
+		t.Errorf("Invalid key error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	if err := CheckKeyStrength(nil, 2048); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+// EOF
@@ -0,0 +1,98 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// KeyPair groups an RSA private key with its public key, so that both do not have to be passed
+// around separately.
+type KeyPair struct {
+	Private *rsa.PrivateKey
+	Public  *rsa.PublicKey
+}
+
+// NewKeyPair creates a KeyPair with a new RSA 4096-bit key, see CreateRSAKeyPair.
+func NewKeyPair() (*KeyPair, error) {
+	privateKey, publicKey, err := CreateRSAKeyPair()
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return &KeyPair{Private: privateKey, Public: publicKey}, nil
+}
+
+// KeyPairFromPrivateKey creates a KeyPair from an existing private key, e.g. loaded with LoadPrivateKey.
+func KeyPairFromPrivateKey(privKey *rsa.PrivateKey) (*KeyPair, error) {
+	if privKey == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	return &KeyPair{Private: privKey, Public: &privKey.PublicKey}, nil
+}
+
+// Sign calculates the SHA-256 digest of msg and returns its RSA-PSS signature.
+func (kp *KeyPair) Sign(msg []byte) ([]byte, error) {
+	if kp.Private == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	sig, err := SignPSSByteArray(kp.Private, Sha256bytes2bytes(msg))
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return sig, nil
+}
+
+// Verify checks the RSA-PSS signature sig of msg. If no error is returned, the verification was successful.
+func (kp *KeyPair) Verify(msg []byte, sig []byte) error {
+	return VerifyPSSByteArray(kp.Public, sig, msg)
+}
+
+// WriteFiles writes the private key to the file basename and the public key to basename.pub, both in
+// PEM format. Like CreateRSAKeyPair2File, an error is returned if one of the files already exists. If
+// the keys cannot be written completely, both files are removed again.
+func (kp *KeyPair) WriteFiles(basename string) error {
+	if kp.Private == nil || kp.Public == nil {
+		return fmt.Errorf("%s:Error, %w", CurrentFunctionName(), ErrNilKey)
+	}
+	privName, pubName := basename, basename+publicKeyFileSuffix
+	privKeyFile, pubKeyFile, err := createKeyPairFiles(privName, pubName)
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if err = WriteRsaPrivateKey(privKeyFile, kp.Private); err != nil {
+		err = errors.New(CurrentFunctionName() + ":private key writing:" + err.Error())
+	} else if err = WriteRsaPublicKey(pubKeyFile, kp.Public); err != nil {
+		err = errors.New(CurrentFunctionName() + ":public key writing:" + err.Error())
+	}
+	privKeyFile.Close()
+	pubKeyFile.Close()
+	if err != nil {
+		_ = os.Remove(privName)
+		_ = os.Remove(pubName)
+		return err
+	}
+	return nil
+}
+
+// EnrollDevice implements the proof-of-possession flow of a device enrollment. It creates a new RSA
+// 4096-bit key, returns it with its PEM-encoded public key, and the RSA-PSS signature of the SHA-256
+// digest of the server challenge. The server verifies the signature with the public key.
+func EnrollDevice(challenge []byte) (priv *rsa.PrivateKey, pubPEM []byte, sig []byte, err error) {
+	priv, pub, err := CreateRSAKeyPair()
+	if err != nil {
+		return nil, nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	block, err := publicKeyPemBlock(pub)
+	if err != nil {
+		return nil, nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	sig, err = SignPSSByteArray(priv, Sha256bytes2bytes(challenge))
+	if err != nil {
+		return nil, nil, nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return priv, pem.EncodeToMemory(block), sig, nil
+}
+
+// EOF
@@ -0,0 +1,85 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyPairSignVerify(t *testing.T) {
+	kp, err := KeyPairFromPrivateKey(testPrivateKey(t))
+	if err != nil {
+		t.Fatalf("KeyPairFromPrivateKey failed:%s\n", err)
+	}
+	msg := []byte("key pair message")
+	sig, err := kp.Sign(msg)
+	if err != nil {
+		t.Fatalf("Sign failed:%s\n", err)
+	}
+	if err := kp.Verify(msg, sig); err != nil {
+		t.Errorf("Verify failed:%s\n", err)
+	}
+	if err := kp.Verify([]byte("other message"), sig); err == nil {
+		t.Errorf("Verify of a different message should fail\n")
+	}
+}
+
+func TestKeyPairWriteFiles(t *testing.T) {
+	kp, err := KeyPairFromPrivateKey(testPrivateKey(t))
+	if err != nil {
+		t.Fatalf("KeyPairFromPrivateKey failed:%s\n", err)
+	}
+	basename := filepath.Join(t.TempDir(), "key")
+	if err := kp.WriteFiles(basename); err != nil {
+		t.Fatalf("WriteFiles failed:%s\n", err)
+	}
+	privKey, err := LoadPrivateKey(basename)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey failed:%s\n", err)
+	}
+	pubKey, err := LoadPublicKey(basename + ".pub")
+	if err != nil {
+		t.Fatalf("LoadPublicKey failed:%s\n", err)
+	}
+	if !privKey.Equal(kp.Private) || !pubKey.Equal(kp.Public) {
+		t.Errorf("Keys read back differ from the written ones\n")
+	}
+	if err := kp.WriteFiles(basename); err == nil {
+		t.Errorf("WriteFiles should refuse to overwrite existing files\n")
+	}
+	// an invalid public key lets the writing fail after the files were created
+	broken := &KeyPair{Private: kp.Private, Public: &rsa.PublicKey{E: 65537}}
+	brokenName := filepath.Join(t.TempDir(), "broken")
+	if err := broken.WriteFiles(brokenName); err == nil {
+		t.Fatalf("WriteFiles of an invalid public key should fail\n")
+	}
+	for _, filename := range []string{brokenName, brokenName + ".pub"} {
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			t.Errorf("File %s should be removed, stat:%v\n", filename, err)
+		}
+	}
+	if err := kp.WriteFiles(brokenName); err != nil {
+		t.Errorf("Retry of WriteFiles failed:%s\n", err)
+	}
+}
+
+func TestEnrollDevice(t *testing.T) {
+	challenge := []byte("server challenge 4711")
+	priv, pubPEM, sig, err := EnrollDevice(challenge)
+	if err != nil {
+		t.Fatalf("EnrollDevice failed:%s\n", err)
+	}
+	pub, err := Pem2RsaPublicKey(pubPEM)
+	if err != nil {
+		t.Fatalf("Pem2RsaPublicKey failed:%s\n", err)
+	}
+	if !pub.Equal(&priv.PublicKey) {
+		t.Errorf("Returned public key does not belong to the private key\n")
+	}
+	if err := VerifyPSSByteArray(pub, sig, challenge); err != nil {
+		t.Errorf("Verification of the challenge signature failed:%s\n", err)
+	}
+}
+
+// EOF
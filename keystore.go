@@ -0,0 +1,75 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"sync"
+)
+
+// InMemoryKeystore holds RSA keys by id without touching the disk, e.g. for ephemeral services. It is
+// safe for concurrent use. Create it with NewInMemoryKeystore.
+type InMemoryKeystore struct {
+	mutex sync.RWMutex
+	keys  map[string]*rsa.PrivateKey
+}
+
+// NewInMemoryKeystore creates an empty InMemoryKeystore.
+func NewInMemoryKeystore() *InMemoryKeystore {
+	return &InMemoryKeystore{keys: map[string]*rsa.PrivateKey{}}
+}
+
+// key returns the key with the id or an error if there is none.
+func (ks *InMemoryKeystore) key(id string) (*rsa.PrivateKey, error) {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+	key, ok := ks.keys[id]
+	if !ok {
+		return nil, errors.New("no key with id " + id)
+	}
+	return key, nil
+}
+
+// Generate creates a new RSA key of the given size, see CreateRSAKeyPairBits, and stores it under the id.
+// An existing key with the same id is not replaced, an error is returned instead.
+func (ks *InMemoryKeystore) Generate(id string, bits int) error {
+	if err := checkRSAKeyPairBits(bits); err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	key, err := rsa.GenerateKey(rand.Reader, bits) // outside of the lock, this can take a while
+	if err != nil {
+		return errors.New(CurrentFunctionName() + ":key creation:" + err.Error())
+	}
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	if _, ok := ks.keys[id]; ok {
+		return errors.New(CurrentFunctionName() + ":key with id " + id + " already exists")
+	}
+	ks.keys[id] = key
+	return nil
+}
+
+// Sign returns the RSA-PSS signature of the digest created with the key with the id.
+func (ks *InMemoryKeystore) Sign(id string, digest []byte) ([]byte, error) {
+	key, err := ks.key(id)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return SignPSSByteArray(key, digest)
+}
+
+// PublicKeyPEM returns the PEM-encoded public key of the key with the id.
+func (ks *InMemoryKeystore) PublicKeyPEM(id string) ([]byte, error) {
+	key, err := ks.key(id)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	block, err := publicKeyPemBlock(&key.PublicKey)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// EOF
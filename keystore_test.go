@@ -0,0 +1,56 @@
+package go_libs
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestInMemoryKeystore(t *testing.T) {
+	ks := NewInMemoryKeystore()
+	ids := []string{"first", "second"}
+	var wg sync.WaitGroup
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			errs[i] = ks.Generate(id, testBitSize)
+		}(i, id)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Generate of %s failed:%s\n", ids[i], err)
+		}
+	}
+
+	msg := []byte("keystore message")
+	for _, id := range ids {
+		sig, err := ks.Sign(id, Sha256bytes2bytes(msg))
+		if err != nil {
+			t.Fatalf("Sign with %s failed:%s\n", id, err)
+		}
+		pubPEM, err := ks.PublicKeyPEM(id)
+		if err != nil {
+			t.Fatalf("PublicKeyPEM of %s failed:%s\n", id, err)
+		}
+		pub, err := Pem2RsaPublicKey(pubPEM)
+		if err != nil {
+			t.Fatalf("Pem2RsaPublicKey failed:%s\n", err)
+		}
+		if err := VerifyPSSByteArray(pub, sig, msg); err != nil {
+			t.Errorf("Verification with key %s failed:%s\n", id, err)
+		}
+	}
+	if err := ks.Generate("first", testBitSize); err == nil {
+		t.Errorf("Generate should not replace an existing key\n")
+	}
+	if err := ks.Generate("odd", 2049); err == nil {
+		t.Errorf("Generate should reject unsupported key sizes\n")
+	}
+	if _, err := ks.Sign("unknown", Sha256bytes2bytes(msg)); err == nil {
+		t.Errorf("Sign with an unknown id should fail\n")
+	}
+}
+
+// EOF
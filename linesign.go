@@ -0,0 +1,78 @@
+package go_libs
+
+import (
+	"bufio"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// lineSignatureSeparator separates a line from its appended signature.
+const lineSignatureSeparator = "\t"
+
+// maxSignedLineSize is the maximum length of a line read by SignLines and VerifyLines.
+const maxSignedLineSize = 1024 * 1024
+
+// newLineScanner returns a scanner reading lines of up to maxSignedLineSize bytes.
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxSignedLineSize)
+	return scanner
+}
+
+// SignLines reads r line by line and writes each line to w, followed by a tab and the base64 encoded
+// RSA-PSS signature of the line. This adds tamper-evidence to streaming logs. Use VerifyLines to check
+// the output.
+func SignLines(key *rsa.PrivateKey, r io.Reader, w io.Writer) error {
+	if key == nil {
+		return fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		sig, err := SignPSSByteArray(key, Sha256bytes2bytes([]byte(line)))
+		if err != nil {
+			return errors.New(CurrentFunctionName() + ":" + err.Error())
+		}
+		if _, err := io.WriteString(w, line+lineSignatureSeparator+base64.StdEncoding.EncodeToString(sig)+"\n"); err != nil {
+			return errors.New(CurrentFunctionName() + ":" + err.Error())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return nil
+}
+
+// VerifyLines reads the output of SignLines and verifies the signature of each line. The error names
+// the first line (counting from 1) which is not signed correctly.
+func VerifyLines(pub *rsa.PublicKey, r io.Reader) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	scanner := newLineScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := scanner.Text()
+		idx := strings.LastIndex(text, lineSignatureSeparator)
+		if idx < 0 {
+			return fmt.Errorf("%s:%w:line %d:no signature", CurrentFunctionName(), ErrParse, lineNo)
+		}
+		sig, err := base64.StdEncoding.DecodeString(text[idx+len(lineSignatureSeparator):])
+		if err != nil {
+			return fmt.Errorf("%s:%w:line %d:%s", CurrentFunctionName(), ErrParse, lineNo, err.Error())
+		}
+		if err := VerifyPSSByteArray(pub, sig, []byte(text[:idx])); err != nil {
+			return errors.New(CurrentFunctionName() + ":line " + strconv.Itoa(lineNo) + ":" + err.Error())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return nil
+}
+
+// EOF
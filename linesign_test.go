@@ -0,0 +1,29 @@
+package go_libs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSignLines(t *testing.T) {
+	key := testPrivateKey(t)
+	input := "first line\nsecond\twith tab\n\nlast line\n"
+	var signed bytes.Buffer
+	if err := SignLines(key, strings.NewReader(input), &signed); err != nil {
+		t.Fatalf("SignLines failed:%s\n", err)
+	}
+	if lines := strings.Count(signed.String(), "\n"); lines != 4 {
+		t.Errorf("Line count error, is:%d, expected:%d\n", lines, 4)
+	}
+	if err := VerifyLines(&key.PublicKey, bytes.NewReader(signed.Bytes())); err != nil {
+		t.Errorf("VerifyLines failed:%s\n", err)
+	}
+	tampered := strings.Replace(signed.String(), "last line", "last lime", 1)
+	err := VerifyLines(&key.PublicKey, strings.NewReader(tampered))
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("Tampered line error, is:%v, expected error for line 4\n", err)
+	}
+}
+
+// EOF
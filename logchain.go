@@ -0,0 +1,59 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// SignedLogRecord is an entry of a hash-chained, signed log. Each record contains the hash of its
+// predecessor, so that removing, reordering, or modifying records breaks the chain.
+type SignedLogRecord struct {
+	Data      []byte
+	PrevHash  []byte // Hash of the previous record, empty for the first record
+	Hash      []byte // SHA-256(PrevHash || Data)
+	Signature []byte // RSA-PSS signature of Hash
+}
+
+// SignLogRecord creates the next record of a log chain. prevHash is the Hash of the previous record or
+// nil for the first record.
+func SignLogRecord(key *rsa.PrivateKey, prevHash []byte, data []byte) (SignedLogRecord, error) {
+	if key == nil {
+		return SignedLogRecord{}, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	record := SignedLogRecord{Data: data, PrevHash: prevHash}
+	record.Hash = Sha256bytes2bytes(append(append([]byte{}, prevHash...), data...))
+	sig, err := SignPSSByteArray(key, record.Hash)
+	if err != nil {
+		return SignedLogRecord{}, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	record.Signature = sig
+	return record, nil
+}
+
+// VerifyLogChain walks the chain and verifies the hash linkage and the signature of each record. It
+// returns -1 if the whole chain is intact. Otherwise, it returns the index of the first broken record
+// and an error describing the break.
+func VerifyLogChain(pub *rsa.PublicKey, records []SignedLogRecord) (int, error) {
+	if pub == nil {
+		return 0, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	var prevHash []byte
+	for i, record := range records {
+		hashInput := append(append([]byte{}, record.PrevHash...), record.Data...)
+		switch {
+		case !bytes.Equal(record.PrevHash, prevHash):
+			return i, fmt.Errorf("%s:%w:record %d:not linked to its predecessor", CurrentFunctionName(), ErrVerification, i)
+		case !bytes.Equal(record.Hash, Sha256bytes2bytes(hashInput)):
+			return i, fmt.Errorf("%s:%w:record %d:hash mismatch", CurrentFunctionName(), ErrVerification, i)
+		}
+		if err := VerifyPSSByteArray(pub, record.Signature, hashInput); err != nil {
+			return i, fmt.Errorf("%s:record %d:signature:%w", CurrentFunctionName(), i, err)
+		}
+		prevHash = record.Hash
+	}
+	return -1, nil
+}
+
+// EOF
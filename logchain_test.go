@@ -0,0 +1,48 @@
+package go_libs
+
+import (
+	"errors"
+	"testing"
+)
+
+// signTestLogChain creates a log chain with one record per entry.
+func signTestLogChain(t *testing.T, entries ...string) []SignedLogRecord {
+	var records []SignedLogRecord
+	var prevHash []byte
+	for _, entry := range entries {
+		record, err := SignLogRecord(testPrivateKey(t), prevHash, []byte(entry))
+		if err != nil {
+			t.Fatalf("SignLogRecord failed:%s\n", err)
+		}
+		records = append(records, record)
+		prevHash = record.Hash
+	}
+	return records
+}
+
+func TestVerifyLogChain(t *testing.T) {
+	pub := &testPrivateKey(t).PublicKey
+	records := signTestLogChain(t, "login alice", "read file", "write file", "logout alice")
+	if idx, err := VerifyLogChain(pub, records); idx != -1 || err != nil {
+		t.Errorf("Intact chain error, index:%d, error:%v\n", idx, err)
+	}
+
+	tampered := append([]SignedLogRecord{}, records...)
+	tampered[2].Data = []byte("delete file")
+	if idx, err := VerifyLogChain(pub, tampered); idx != 2 || !errors.Is(err, ErrVerification) {
+		t.Errorf("Modified record error, index is:%d, expected:2, error:%v\n", idx, err)
+	}
+
+	removed := append(append([]SignedLogRecord{}, records[:2]...), records[3:]...)
+	if idx, err := VerifyLogChain(pub, removed); idx != 2 || !errors.Is(err, ErrVerification) {
+		t.Errorf("Removed record error, index is:%d, expected:2, error:%v\n", idx, err)
+	}
+
+	forged := append([]SignedLogRecord{}, records...)
+	forged[1].Signature = forged[2].Signature
+	if idx, err := VerifyLogChain(pub, forged); idx != 1 || !errors.Is(err, ErrVerification) {
+		t.Errorf("Wrong signature error, index is:%d, expected:1, error:%v\n", idx, err)
+	}
+}
+
+// EOF
@@ -0,0 +1,75 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// signedManifest is the JSON format created by CreateSignedManifest. Files maps the file names to the
+// hex-encoded SHA-256 digests of their contents. Signature is the RSA-PSS signature of the JSON
+// encoding of Files.
+type signedManifest struct {
+	Files     map[string]string `json:"files"`
+	Signature []byte            `json:"signature"`
+}
+
+// CreateSignedManifest creates a JSON manifest mapping the base names of the files to their SHA-256
+// digests and signs it with RSA-PSS. Files with the same base name are rejected, as the manifest is
+// verified against a single directory, see VerifySignedManifest.
+func CreateSignedManifest(key *rsa.PrivateKey, files []string) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	manifest := signedManifest{Files: map[string]string{}}
+	for _, file := range files {
+		name := filepath.Base(file)
+		if _, ok := manifest.Files[name]; ok {
+			return nil, errors.New(CurrentFunctionName() + ":duplicate file name " + name)
+		}
+		digest, err := Sha256FileHex(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+		}
+		manifest.Files[name] = digest
+	}
+	filesJSON, err := json.Marshal(manifest.Files) // map keys are sorted, so the encoding is stable
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if manifest.Signature, err = SignPSSByteArray(key, Sha256bytes2bytes(filesJSON)); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return json.Marshal(manifest)
+}
+
+// VerifySignedManifest verifies the signature of a manifest created by CreateSignedManifest. Then, it
+// hashes each listed file in baseDir again and compares the digest. If no error is returned, the
+// manifest is authentic and all files are unmodified.
+func VerifySignedManifest(pub *rsa.PublicKey, manifest []byte, baseDir string) error {
+	var m signedManifest
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return fmt.Errorf("%s:%w:parsing manifest:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	filesJSON, err := json.Marshal(m.Files)
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := VerifyPSSByteArray(pub, m.Signature, filesJSON); err != nil {
+		return fmt.Errorf("%s:signature verification:%w", CurrentFunctionName(), err)
+	}
+	for name, expected := range m.Files {
+		digest, err := Sha256FileHex(filepath.Join(baseDir, filepath.Base(name)))
+		if err != nil {
+			return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+		}
+		if digest != expected {
+			return fmt.Errorf("%s:%w:file %s was modified", CurrentFunctionName(), ErrVerification, name)
+		}
+	}
+	return nil
+}
+
+// EOF
@@ -0,0 +1,38 @@
+package go_libs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignedManifest(t *testing.T) {
+	key := testPrivateKey(t)
+	dir := t.TempDir()
+	var files []string
+	for _, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte("content of "+name), 0600); err != nil {
+			t.Fatalf("WriteFile failed:%s\n", err)
+		}
+		files = append(files, filename)
+	}
+	manifest, err := CreateSignedManifest(key, files)
+	if err != nil {
+		t.Fatalf("CreateSignedManifest failed:%s\n", err)
+	}
+	if err := VerifySignedManifest(&key.PublicKey, manifest, dir); err != nil {
+		t.Errorf("VerifySignedManifest failed:%s\n", err)
+	}
+
+	if err := os.WriteFile(files[1], []byte("modified"), 0600); err != nil {
+		t.Fatalf("WriteFile failed:%s\n", err)
+	}
+	err = VerifySignedManifest(&key.PublicKey, manifest, dir)
+	if err == nil || !strings.Contains(err.Error(), "b.bin") {
+		t.Errorf("Expected an error for the modified file b.bin, got:%v\n", err)
+	}
+}
+
+// EOF
@@ -0,0 +1,83 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// NonceStore remembers nonces to detect replayed messages. Seen reports if the nonce was seen before and
+// records it, so the check and the recording must be atomic.
+type NonceStore interface {
+	Seen(nonce []byte) bool
+}
+
+// MemoryNonceStore is a NonceStore keeping all nonces in memory. It never forgets a nonce, so it is
+// suited for tests and short-lived processes. It is safe for concurrent use.
+type MemoryNonceStore struct {
+	mutex  sync.Mutex
+	nonces map[string]struct{}
+}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: map[string]struct{}{}}
+}
+
+// Seen reports if the nonce was seen before and records it.
+func (s *MemoryNonceStore) Seen(nonce []byte) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.nonces[string(nonce)]; ok {
+		return true
+	}
+	s.nonces[string(nonce)] = struct{}{}
+	return false
+}
+
+// nonceSignedData returns the data covered by the signature: length of nonce (4 bytes, big-endian) || nonce || msg
+func nonceSignedData(msg string, nonce []byte) []byte {
+	data := make([]byte, 4, 4+len(nonce)+len(msg))
+	binary.BigEndian.PutUint32(data, uint32(len(nonce)))
+	data = append(data, nonce...)
+	return append(data, msg...)
+}
+
+// SignWithNonce returns the RSA-PSS signature over the nonce and the message, see VerifyWithNonce.
+func SignWithNonce(key *rsa.PrivateKey, msg string, nonce []byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	return SignPSSByteArray(key, Sha256bytes2bytes(nonceSignedData(msg, nonce)))
+}
+
+// VerifyWithNonce verifies a signature created by SignWithNonce and rejects a nonce which was already
+// used, to prevent replay. The nonce is only recorded in the store if the signature is valid, so forged
+// messages cannot use up nonces.
+func VerifyWithNonce(pub *rsa.PublicKey, msg string, nonce, sig []byte, store NonceStore) error {
+	if store == nil {
+		return errors.New(CurrentFunctionName() + ":Error, nonce store is nil")
+	}
+	if len(nonce) == 0 {
+		return fmt.Errorf("%s:%w:Error, nonce is empty", CurrentFunctionName(), ErrParse)
+	}
+	if err := VerifyPSSByteArray(pub, sig, nonceSignedData(msg, nonce)); err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if store.Seen(nonce) {
+		return fmt.Errorf("%s:%w:nonce was already used, replay?", CurrentFunctionName(), ErrVerification)
+	}
+	return nil
+}
+
+// MessageID returns a content-addressed ID of the message, the unpadded base64url encoded SHA-256
+// digest. Identical messages get the same ID, so it can be used to deduplicate signed messages, e.g.
+// as the key of a NonceStore.
+func MessageID(msg []byte) string {
+	return base64.RawURLEncoding.EncodeToString(Sha256bytes2bytes(msg))
+}
+
+// EOF
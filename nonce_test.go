@@ -0,0 +1,47 @@
+package go_libs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyWithNonceRejectsReuse(t *testing.T) {
+	key := testPrivateKey(t)
+	store := NewMemoryNonceStore()
+	const msg = "transfer 100"
+	nonce := []byte("nonce-0001")
+	sig, err := SignWithNonce(key, msg, nonce)
+	if err != nil {
+		t.Fatalf("SignWithNonce failed:%s\n", err)
+	}
+	if err := VerifyWithNonce(&key.PublicKey, msg, []byte("nonce-0002"), sig, store); err == nil {
+		t.Errorf("Signature must not verify with a different nonce\n")
+	}
+	if err := VerifyWithNonce(&key.PublicKey, msg, nonce, sig, store); err != nil {
+		t.Fatalf("First verification failed:%s\n", err)
+	}
+	if err := VerifyWithNonce(&key.PublicKey, msg, nonce, sig, store); !errors.Is(err, ErrVerification) {
+		t.Errorf("Second use of the nonce error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	if err := VerifyWithNonce(&key.PublicKey, msg, nonce, sig, nil); err == nil || errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil store error, is:%v, expected an error not wrapping %v\n", err, ErrNilKey)
+	}
+	if err := VerifyWithNonce(nil, msg, nonce, sig, store); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+func TestMessageID(t *testing.T) {
+	id := MessageID([]byte("message"))
+	if again := MessageID([]byte("message")); again != id {
+		t.Errorf("MessageID of identical content error, is:%s, expected:%s\n", again, id)
+	}
+	if other := MessageID([]byte("message2")); other == id {
+		t.Errorf("MessageID of different content should differ, is:%s\n", other)
+	}
+	if len(id) != 43 {
+		t.Errorf("MessageID length error, is:%d, expected:%d\n", len(id), 43)
+	}
+}
+
+// EOF
@@ -0,0 +1,68 @@
+package go_libs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// EncryptOAEPHash encrypts a small plaintext with RSA-OAEP for the owner of the public key. The hash is
+// used for both the label and MGF1, e.g. crypto.SHA1 or crypto.SHA512 for interop with other systems.
+// No label is used. The plaintext can be at most the key size - 2 * hash size - 2 bytes long.
+func EncryptOAEPHash(pub *rsa.PublicKey, hash crypto.Hash, plaintext []byte) ([]byte, error) {
+	if pub == nil {
+		return nil, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if !hash.Available() {
+		return nil, errors.New(CurrentFunctionName() + ":hash function not available")
+	}
+	if maxLen := pub.Size() - 2*hash.Size() - 2; len(plaintext) > maxLen {
+		return nil, errors.New(CurrentFunctionName() + ":plaintext too long, " + strconv.Itoa(len(plaintext)) +
+			" bytes, maximum for this key and hash is " + strconv.Itoa(maxLen) + " bytes")
+	}
+	ciphertext, err := rsa.EncryptOAEP(hash.New(), rand.Reader, pub, plaintext, nil)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return ciphertext, nil
+}
+
+// DecryptOAEPHash decrypts a ciphertext created by EncryptOAEPHash with the same hash.
+func DecryptOAEPHash(priv *rsa.PrivateKey, hash crypto.Hash, ciphertext []byte) ([]byte, error) {
+	if priv == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if !hash.Available() {
+		return nil, errors.New(CurrentFunctionName() + ":hash function not available")
+	}
+	plaintext, err := rsa.DecryptOAEP(hash.New(), rand.Reader, priv, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":decryption failed, wrong key or hash?")
+	}
+	return plaintext, nil
+}
+
+// EncryptOAEP encrypts a small plaintext, e.g. a symmetric key, with RSA-OAEP, SHA-256, and no label for
+// the owner of the public key. The plaintext can be at most the key size - 66 bytes long, e.g. 446 bytes
+// for a 4096-bit key. Use HybridEncrypt for larger payloads.
+func EncryptOAEP(pub *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+	ciphertext, err := EncryptOAEPHash(pub, crypto.SHA256, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return ciphertext, nil
+}
+
+// DecryptOAEP decrypts a ciphertext created by EncryptOAEP.
+func DecryptOAEP(priv *rsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	plaintext, err := DecryptOAEPHash(priv, crypto.SHA256, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return plaintext, nil
+}
+
+// EOF
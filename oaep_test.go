@@ -0,0 +1,59 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOAEPHashRoundTrip(t *testing.T) {
+	key := testPrivateKey(t)
+	plaintext := []byte("small secret")
+	for _, hash := range []crypto.Hash{crypto.SHA256, crypto.SHA512} {
+		ciphertext, err := EncryptOAEPHash(&key.PublicKey, hash, plaintext)
+		if err != nil {
+			t.Fatalf("EncryptOAEPHash failed for %s:%s\n", hash, err)
+		}
+		decrypted, err := DecryptOAEPHash(key, hash, ciphertext)
+		if err != nil {
+			t.Fatalf("DecryptOAEPHash failed for %s:%s\n", hash, err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("Round-trip error for %s, is:%s, expected:%s\n", hash, decrypted, plaintext)
+		}
+	}
+	ciphertext, err := EncryptOAEPHash(&key.PublicKey, crypto.SHA512, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptOAEPHash failed:%s\n", err)
+	}
+	if _, err := DecryptOAEPHash(key, crypto.SHA256, ciphertext); err == nil {
+		t.Errorf("Decryption with a different hash should fail\n")
+	}
+}
+
+func TestOAEPRoundTrip(t *testing.T) {
+	key := testPrivateKey(t)
+	maxLen := key.Size() - 66
+	plaintext := bytes.Repeat([]byte{0x42}, maxLen)
+	ciphertext, err := EncryptOAEP(&key.PublicKey, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptOAEP failed:%s\n", err)
+	}
+	decrypted, err := DecryptOAEP(key, ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptOAEP failed:%s\n", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Round-trip error, is:%x, expected:%x\n", decrypted, plaintext)
+	}
+	if _, err := EncryptOAEP(&key.PublicKey, append(plaintext, 0x42)); err == nil || !strings.Contains(err.Error(), "plaintext too long") {
+		t.Errorf("Too long plaintext error, is:%v, expected an error about the maximum length\n", err)
+	}
+	if _, err := DecryptOAEP(nil, ciphertext); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+// EOF
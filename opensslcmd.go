@@ -0,0 +1,47 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shellQuote quotes s for use as a single argument in a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// OpenSSLVerifyCommand writes the public key, the message, and the RSA-PSS signature (as created by
+// SignPSSByteArray) to files in a new temporary directory and returns the openssl command to verify
+// the signature independently, e.g. for interop debugging. The caller should remove the directory
+// when it is not needed anymore.
+func OpenSSLVerifyCommand(pub *rsa.PublicKey, msg []byte, sig []byte) (string, error) {
+	if pub == nil {
+		return "", fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	block, err := publicKeyPemBlock(pub)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	dir, err := os.MkdirTemp("", "go_libs-verify-")
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	pubFile := filepath.Join(dir, "key"+publicKeyFileSuffix)
+	msgFile := filepath.Join(dir, "msg")
+	sigFile := filepath.Join(dir, "msg.sig")
+	for filename, content := range map[string][]byte{pubFile: pem.EncodeToMemory(block), msgFile: msg, sigFile: sig} {
+		if err := os.WriteFile(filename, content, 0600); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+		}
+	}
+	return "openssl dgst -sha256 -sigopt rsa_padding_mode:pss -sigopt rsa_pss_saltlen:auto" +
+		" -verify " + shellQuote(pubFile) + " -signature " + shellQuote(sigFile) + " " + shellQuote(msgFile), nil
+}
+
+// EOF
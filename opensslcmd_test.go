@@ -0,0 +1,41 @@
+package go_libs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenSSLVerifyCommand(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("verify me with openssl")
+	sig, err := SignPSSByteArray(key, Sha256bytes2bytes(msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArray failed:%s\n", err)
+	}
+	cmd, err := OpenSSLVerifyCommand(&key.PublicKey, msg, sig)
+	if err != nil {
+		t.Fatalf("OpenSSLVerifyCommand failed:%s\n", err)
+	}
+	fields := strings.Fields(cmd)
+	msgFile := strings.Trim(fields[len(fields)-1], "'")
+	defer os.RemoveAll(filepath.Dir(msgFile))
+	for _, name := range []string{"key.pub", "msg", "msg.sig"} {
+		filename := filepath.Join(filepath.Dir(msgFile), name)
+		if !strings.Contains(cmd, filename) {
+			t.Errorf("Command does not reference %s:%s\n", filename, cmd)
+		}
+		if _, err := os.Stat(filename); err != nil {
+			t.Errorf("File %s not written:%s\n", filename, err)
+		}
+	}
+	if _, err := exec.LookPath("openssl"); err == nil {
+		if out, err := exec.Command("sh", "-c", cmd).CombinedOutput(); err != nil {
+			t.Errorf("openssl verification failed:%s:%s\n", err, out)
+		}
+	}
+}
+
+// EOF
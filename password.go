@@ -0,0 +1,79 @@
+package go_libs
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// parameters of HashPasswordArgon2id, the second recommended option of RFC 9106
+const (
+	argon2idTime    = 3
+	argon2idMemory  = 64 * 1024 // KiB
+	argon2idThreads = 4
+	argon2idSaltLen = 16
+	argon2idKeyLen  = 32
+)
+
+// upper bounds of the parameters accepted by VerifyPasswordArgon2id. The parameters are read from the
+// encoded hash, so a crafted hash could otherwise allocate terabytes or burn CPU for hours. The number
+// of threads is limited to 255 by its type.
+const (
+	argon2idMaxTime   = 10
+	argon2idMaxMemory = 1024 * 1024 // KiB, 1 GiB
+)
+
+// HashPasswordArgon2id hashes the password for storage with Argon2id and a random salt. The result is
+// self-describing in the common PHC layout $argon2id$v=19$m=65536,t=3,p=4$salt$hash with unpadded
+// base64 salt and hash, which is also understood by other libraries and the argon2 CLI. Use
+// VerifyPasswordArgon2id to check a password. For the derivation of encryption keys use DeriveKeyPBKDF2.
+func HashPasswordArgon2id(password string) (string, error) {
+	salt, err := GenerateSalt(argon2idSaltLen)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	hash := argon2.IDKey([]byte(password), salt, argon2idTime, argon2idMemory, argon2idThreads, argon2idKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2idMemory, argon2idTime,
+		argon2idThreads, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(hash)), nil
+}
+
+// VerifyPasswordArgon2id checks the password against an encoded hash of HashPasswordArgon2id or another
+// tool using the same layout. The parameters are taken from the encoded hash and the hashes are compared
+// in constant time. false and no error is returned for a wrong password. A malformed encoded hash
+// results in an error wrapping ErrParse.
+func VerifyPasswordArgon2id(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return false, fmt.Errorf("%s:%w:not an argon2id hash", CurrentFunctionName(), ErrParse)
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version ||
+		parts[2] != fmt.Sprintf("v=%d", version) {
+		return false, fmt.Errorf("%s:%w:unsupported argon2 version %s", CurrentFunctionName(), ErrParse, parts[2])
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil ||
+		memory == 0 || time == 0 || threads == 0 || parts[3] != fmt.Sprintf("m=%d,t=%d,p=%d", memory, time, threads) {
+		return false, fmt.Errorf("%s:%w:invalid parameters %s", CurrentFunctionName(), ErrParse, parts[3])
+	}
+	if memory > argon2idMaxMemory || time > argon2idMaxTime {
+		return false, fmt.Errorf("%s:%w:parameters %s exceed the maximum m=%d,t=%d", CurrentFunctionName(),
+			ErrParse, parts[3], argon2idMaxMemory, argon2idMaxTime)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("%s:%w:invalid salt", CurrentFunctionName(), ErrParse)
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return false, fmt.Errorf("%s:%w:invalid hash", CurrentFunctionName(), ErrParse)
+	}
+	computed := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(hash)))
+	return SecureCompare(computed, hash), nil
+}
+
+// EOF
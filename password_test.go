@@ -0,0 +1,41 @@
+package go_libs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestArgon2idPassword(t *testing.T) {
+	encoded, err := HashPasswordArgon2id("correct horse")
+	if err != nil {
+		t.Fatalf("HashPasswordArgon2id failed:%s\n", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=65536,t=3,p=4$") {
+		t.Errorf("Encoded hash format error, is:%s\n", encoded)
+	}
+	if ok, err := VerifyPasswordArgon2id("correct horse", encoded); err != nil || !ok {
+		t.Errorf("Correct password error, is:%v, %v\n", ok, err)
+	}
+	if ok, err := VerifyPasswordArgon2id("wrong horse", encoded); err != nil || ok {
+		t.Errorf("Wrong password error, is:%v, %v\n", ok, err)
+	}
+	for _, malformed := range []string{"", "$argon2i$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA", "$argon2id$v=16$m=65536,t=3,p=4$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=0,t=3,p=4$c2FsdA$aGFzaA", "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$",
+		"$argon2id$v=19$m=4294967295,t=3,p=4$c2FsdA$aGFzaA", "$argon2id$v=19$m=65536,t=1000000,p=4$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=3,p=4garbage$c2FsdA$aGFzaA", "$argon2id$v=19garbage$m=65536,t=3,p=4$c2FsdA$aGFzaA"} {
+		if _, err := VerifyPasswordArgon2id("correct horse", malformed); !errors.Is(err, ErrParse) {
+			t.Errorf("Malformed hash %s error, is:%v, expected:%v\n", malformed, err, ErrParse)
+		}
+	}
+}
+
+func TestVerifyPasswordArgon2idReference(t *testing.T) {
+	// test vector of the Argon2 reference implementation for password and somesalt
+	encoded := "$argon2id$v=19$m=64,t=2,p=2$c29tZXNhbHQ$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi"
+	if ok, err := VerifyPasswordArgon2id("password", encoded); err != nil || !ok {
+		t.Errorf("Reference hash error, is:%v, %v\n", ok, err)
+	}
+}
+
+// EOF
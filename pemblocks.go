@@ -0,0 +1,58 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+)
+
+// ListPEMBlocks returns the types of all PEM blocks in the buffer in order, e.g. CERTIFICATE,
+// RSA PRIVATE KEY, and PUBLIC KEY. The blocks are not parsed, so this helps to diagnose what is in a
+// pasted bundle. Text between the blocks is ignored. An error is returned if there is no PEM block.
+func ListPEMBlocks(der []byte) ([]string, error) {
+	var types []string
+	for rest := der; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		types = append(types, block.Type)
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%s:%w:no PEM block found", CurrentFunctionName(), ErrParse)
+	}
+	return types, nil
+}
+
+// ParsePublicKeys returns all RSA public keys of a bundle of concatenated PEM blocks in order, e.g. a
+// key-rotation set. PUBLIC KEY (PKIX) and RSA PUBLIC KEY (PKCS#1) blocks are parsed as by
+// Pem2RsaPublicKey. Other blocks like certificates are skipped. An error is returned if a key block
+// cannot be parsed or if the bundle does not contain any RSA public key.
+func ParsePublicKeys(der []byte) ([]*rsa.PublicKey, error) {
+	if len(der) == 0 {
+		return nil, fmt.Errorf("%s:%w:empty input", CurrentFunctionName(), ErrParse)
+	}
+	var keys []*rsa.PublicKey
+	for rest, i := der, 1; ; i++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != pemTypePKIXPublicKey && block.Type != pemTypePKCS1PublicKey {
+			continue
+		}
+		pub, err := parseRsaPublicKeyBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w:PEM block %d:%s", CurrentFunctionName(), ErrParse, i, err.Error())
+		}
+		keys = append(keys, pub)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s:%w:no RSA public key found", CurrentFunctionName(), ErrParse)
+	}
+	return keys, nil
+}
+
+// EOF
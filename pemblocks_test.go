@@ -0,0 +1,90 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListPEMBlocks(t *testing.T) {
+	key := testPrivateKey(t)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bundle test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed:%s\n", err)
+	}
+	pubBlock, err := publicKeyPemBlock(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("publicKeyPemBlock failed:%s\n", err)
+	}
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	bundle = append(bundle, "comment between the blocks\n"...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: pemTypePKCS1PrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(key)})...)
+	bundle = append(bundle, pem.EncodeToMemory(pubBlock)...)
+
+	types, err := ListPEMBlocks(bundle)
+	if err != nil {
+		t.Fatalf("ListPEMBlocks failed:%s\n", err)
+	}
+	expected := []string{"CERTIFICATE", "RSA PRIVATE KEY", "PUBLIC KEY"}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Block types error, is:%v, expected:%v\n", types, expected)
+	}
+	if _, err := ListPEMBlocks([]byte("no pem here")); !errors.Is(err, ErrParse) {
+		t.Errorf("Buffer without PEM error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	key1 := testPrivateKey(t)
+	key2 := newTestPrivateKey(t)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bundle test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key1.PublicKey, key1)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed:%s\n", err)
+	}
+	pubBlock, err := publicKeyPemBlock(&key1.PublicKey)
+	if err != nil {
+		t.Fatalf("publicKeyPemBlock failed:%s\n", err)
+	}
+	bundle := pem.EncodeToMemory(pubBlock)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: pemTypePKCS1PublicKey, Bytes: x509.MarshalPKCS1PublicKey(&key2.PublicKey)})...)
+
+	keys, err := ParsePublicKeys(bundle)
+	if err != nil {
+		t.Fatalf("ParsePublicKeys failed:%s\n", err)
+	}
+	if len(keys) != 2 || !keys[0].Equal(&key1.PublicKey) || !keys[1].Equal(&key2.PublicKey) {
+		t.Errorf("Number of keys error, is:%d, expected:2 keys in bundle order\n", len(keys))
+	}
+	if _, err := ParsePublicKeys(nil); !errors.Is(err, ErrParse) {
+		t.Errorf("Empty input error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	certOnly := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	if _, err := ParsePublicKeys(certOnly); !errors.Is(err, ErrParse) {
+		t.Errorf("Bundle without keys error, is:%v, expected:%v\n", err, ErrParse)
+	}
+	broken := append(bundle, pem.EncodeToMemory(&pem.Block{Type: pemTypePKIXPublicKey, Bytes: []byte("garbage")})...)
+	if _, err := ParsePublicKeys(broken); !errors.Is(err, ErrParse) {
+		t.Errorf("Broken key block error, is:%v, expected:%v\n", err, ErrParse)
+	}
+}
+
+// EOF
@@ -0,0 +1,217 @@
+package go_libs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"hash"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// pemTypeEncryptedPKCS8PrivateKey is the PEM block type of a password-protected PKCS#8 private key.
+const pemTypeEncryptedPKCS8PrivateKey = "ENCRYPTED PRIVATE KEY"
+
+// parameters of the PBES2 encryption of encryptPKCS8: PBKDF2 with HMAC-SHA256 and AES-256-CBC
+const (
+	pkcs8PBKDF2Iterations = 600000 // as recommended by OWASP for PBKDF2-HMAC-SHA256
+	pkcs8SaltSize         = 16
+)
+
+// pkcs8MaxPBKDF2Iterations limits the iteration count of keys decrypted by decryptPKCS8. The count is
+// read from the key file, so a crafted file could otherwise keep the loader busy for hours.
+const pkcs8MaxPBKDF2Iterations = 10000000
+
+// object identifiers of PKCS#5 v2 (RFC 8018) used in encrypted PKCS#8 keys
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo is the ASN.1 structure of an encrypted PKCS#8 key (RFC 5958).
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params are the parameters of the PBES2 encryption scheme.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of PBKDF2. Without PRF, HMAC-SHA1 is used.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// pbkdf2PRF returns the hash function of the PRF of PBKDF2.
+func pbkdf2PRF(prf pkix.AlgorithmIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(prf.Algorithm) == 0, prf.Algorithm.Equal(oidHMACWithSHA1):
+		return sha1.New, nil
+	case prf.Algorithm.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	case prf.Algorithm.Equal(oidHMACWithSHA384):
+		return sha512.New384, nil
+	case prf.Algorithm.Equal(oidHMACWithSHA512):
+		return sha512.New, nil
+	default:
+		return nil, errors.New("unsupported PBKDF2 PRF " + prf.Algorithm.String())
+	}
+}
+
+// pbes2Cipher returns the block cipher constructor and the key length of the PBES2 encryption scheme.
+func pbes2Cipher(oid asn1.ObjectIdentifier) (func([]byte) (cipher.Block, error), int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, nil
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, nil
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, nil
+	case oid.Equal(oidDESEDE3CBC):
+		return des.NewTripleDESCipher, 24, nil
+	default:
+		return nil, 0, errors.New("unsupported encryption scheme " + oid.String())
+	}
+}
+
+// decryptPKCS8 decrypts an encrypted PKCS#8 key (PBES2 with PBKDF2 and AES-CBC or 3DES-CBC) and returns
+// the DER of the unencrypted PKCS#8 key.
+func decryptPKCS8(der []byte, password []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, errors.New("parsing encrypted PKCS#8 key:" + err.Error())
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, errors.New("unsupported encryption " + info.Algorithm.Algorithm.String() + ", only PBES2 is supported")
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.New("parsing PBES2 parameters:" + err.Error())
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, errors.New("unsupported key derivation " + params.KeyDerivationFunc.Algorithm.String())
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, errors.New("parsing PBKDF2 parameters:" + err.Error())
+	}
+	if kdfParams.IterationCount <= 0 || kdfParams.IterationCount > pkcs8MaxPBKDF2Iterations {
+		return nil, errors.New("PBKDF2 iteration count " + strconv.Itoa(kdfParams.IterationCount) + " out of range, maximum is " +
+			strconv.Itoa(pkcs8MaxPBKDF2Iterations))
+	}
+	prf, err := pbkdf2PRF(kdfParams.PRF)
+	if err != nil {
+		return nil, err
+	}
+	newCipher, keyLen, err := pbes2Cipher(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, errors.New("parsing IV:" + err.Error())
+	}
+	block, err := newCipher(pbkdf2.Key(password, kdfParams.Salt, kdfParams.IterationCount, keyLen, prf))
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid IV or encrypted data length")
+	}
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.EncryptedData)
+	// PKCS#7 padding, invalid padding is usually caused by a wrong password
+	padLen := int(plaintext[len(plaintext)-1])
+	if padLen == 0 || padLen > block.BlockSize() {
+		return nil, ErrWrongPassword
+	}
+	for _, b := range plaintext[len(plaintext)-padLen:] {
+		if int(b) != padLen {
+			return nil, ErrWrongPassword
+		}
+	}
+	return plaintext[:len(plaintext)-padLen], nil
+}
+
+// isDERSequence returns true if der consists of exactly one ASN.1 SEQUENCE, as every decrypted PKCS#1 or
+// PKCS#8 key. Garbage caused by a wrong password which passed the padding check is detected by this.
+func isDERSequence(der []byte) bool {
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(der, &raw)
+	return err == nil && len(rest) == 0 && raw.Class == asn1.ClassUniversal && raw.Tag == asn1.TagSequence
+}
+
+// encryptPKCS8 encrypts the DER of an unencrypted PKCS#8 key with PBES2 (PBKDF2 with HMAC-SHA256 and
+// AES-256-CBC) and returns the DER of the encrypted PKCS#8 key, as created by
+// openssl pkcs8 -topk8 -v2 aes-256-cbc -v2prf hmacWithSHA256.
+func encryptPKCS8(der []byte, password []byte) ([]byte, error) {
+	salt := make([]byte, pkcs8SaltSize)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(password, salt, pkcs8PBKDF2Iterations, aes256KeySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	// PKCS#7 padding
+	padLen := aes.BlockSize - len(der)%aes.BlockSize
+	padded := make([]byte, len(der), len(der)+padLen)
+	copy(padded, der)
+	for i := 0; i < padLen; i++ {
+		padded = append(padded, byte(padLen))
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pkcs8PBKDF2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: padded,
+	})
+}
+
+// EOF
@@ -0,0 +1,60 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
+)
+
+// KeyProvider supplies a private key, e.g. fetched from a secret manager. Implementations may return
+// a different key after a rotation.
+type KeyProvider interface {
+	PrivateKey() (*rsa.PrivateKey, error)
+}
+
+// ProviderSigner signs with a key resolved from a KeyProvider. The key is cached for cacheFor, after
+// that it is requested from the provider again. So, externally rotated keys are picked up. A
+// ProviderSigner is safe for concurrent use if the provider is.
+type ProviderSigner struct {
+	provider KeyProvider
+	cacheFor time.Duration
+	mutex    sync.Mutex
+	key      *rsa.PrivateKey
+	fetched  time.Time
+}
+
+// NewProviderSigner creates a ProviderSigner. A cacheFor of 0 resolves the key for every signature.
+func NewProviderSigner(provider KeyProvider, cacheFor time.Duration) *ProviderSigner {
+	return &ProviderSigner{provider: provider, cacheFor: cacheFor}
+}
+
+// PrivateKey returns the cached key or resolves it from the provider if the cache expired.
+func (s *ProviderSigner) PrivateKey() (*rsa.PrivateKey, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.key != nil && time.Since(s.fetched) < s.cacheFor {
+		return s.key, nil
+	}
+	key, err := s.provider.PrivateKey()
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if key == nil {
+		return nil, errors.New(CurrentFunctionName() + ":Error, provider returned nil key")
+	}
+	s.key = key
+	s.fetched = time.Now()
+	return key, nil
+}
+
+// Sign returns the RSA-PSS signature of the digest, see SignPSSByteArray.
+func (s *ProviderSigner) Sign(digest []byte) ([]byte, error) {
+	key, err := s.PrivateKey()
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return SignPSSByteArray(key, digest)
+}
+
+// EOF
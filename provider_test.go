@@ -0,0 +1,38 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+)
+
+// fakeKeyProvider returns a fixed key and counts the requests.
+type fakeKeyProvider struct {
+	key   *rsa.PrivateKey
+	calls int
+}
+
+func (p *fakeKeyProvider) PrivateKey() (*rsa.PrivateKey, error) {
+	p.calls++
+	return p.key, nil
+}
+
+func TestProviderSigner(t *testing.T) {
+	provider := &fakeKeyProvider{key: testPrivateKey(t)}
+	signer := NewProviderSigner(provider, time.Hour)
+	msg := []byte("provided key")
+	for i := 0; i < 2; i++ {
+		sig, err := signer.Sign(Sha256bytes2bytes(msg))
+		if err != nil {
+			t.Fatalf("Sign failed:%s\n", err)
+		}
+		if err := VerifyPSSByteArray(&provider.key.PublicKey, sig, msg); err != nil {
+			t.Errorf("Verification failed:%s\n", err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("Provider call count error, is:%d, expected:1\n", provider.calls)
+	}
+}
+
+// EOF
@@ -0,0 +1,52 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
+)
+
+// RateLimitedSigner signs digests with RSA-PSS, but at most perSecond times per second. It uses a token
+// bucket which allows bursts of up to perSecond signatures. It protects a signing service from abuse.
+// A RateLimitedSigner is safe for concurrent use.
+type RateLimitedSigner struct {
+	key       *rsa.PrivateKey
+	perSecond float64
+	mutex     sync.Mutex
+	tokens    float64
+	last      time.Time
+	now       func() time.Time // clock, replaceable for tests
+}
+
+// NewRateLimitedSigner creates a RateLimitedSigner for the key. The bucket starts full.
+func NewRateLimitedSigner(key *rsa.PrivateKey, perSecond int) *RateLimitedSigner {
+	return &RateLimitedSigner{
+		key:       key,
+		perSecond: float64(perSecond),
+		tokens:    float64(perSecond),
+		last:      time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Sign returns the RSA-PSS signature of the digest, see SignPSSByteArray. If the rate is exceeded, an
+// error is returned and no signature is created.
+func (s *RateLimitedSigner) Sign(digest []byte) ([]byte, error) {
+	s.mutex.Lock()
+	now := s.now()
+	s.tokens += now.Sub(s.last).Seconds() * s.perSecond
+	if s.tokens > s.perSecond {
+		s.tokens = s.perSecond
+	}
+	s.last = now
+	if s.tokens < 1 {
+		s.mutex.Unlock()
+		return nil, errors.New(CurrentFunctionName() + ":rate limit exceeded")
+	}
+	s.tokens--
+	s.mutex.Unlock()
+	return SignPSSByteArray(s.key, digest)
+}
+
+// EOF
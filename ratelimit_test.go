@@ -0,0 +1,28 @@
+package go_libs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitedSignerBurst(t *testing.T) {
+	clock := time.Now()
+	signer := NewRateLimitedSigner(testPrivateKey(t), 2)
+	signer.now = func() time.Time { return clock }
+	signer.last = clock
+	digest := Sha256bytes2bytes([]byte("rate limited"))
+	for i := 0; i < 2; i++ {
+		if _, err := signer.Sign(digest); err != nil {
+			t.Fatalf("Sign %d within the limit failed:%s\n", i, err)
+		}
+	}
+	if _, err := signer.Sign(digest); err == nil {
+		t.Errorf("Sign beyond the limit should be rejected\n")
+	}
+	clock = clock.Add(time.Second)
+	if _, err := signer.Sign(digest); err != nil {
+		t.Errorf("Sign after refill failed:%s\n", err)
+	}
+}
+
+// EOF
@@ -0,0 +1,63 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// RSAPublicOp performs the textbook RSA public-key operation data^e mod n without any padding, like
+// openssl rsautl -encrypt -raw. The result has the size of the key.
+//
+// UNSAFE: raw RSA is malleable and deterministic and leaks information about the plaintext. It is only
+// meant for interop with legacy protocols and for debugging. Use EncryptOAEPHash or HybridEncrypt
+// for encryption and SignPSSByteArray for signatures instead.
+func RSAPublicOp(pub *rsa.PublicKey, data []byte) ([]byte, error) {
+	if pub == nil {
+		return nil, fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	m := new(big.Int).SetBytes(data)
+	if m.Cmp(pub.N) >= 0 {
+		return nil, errors.New(CurrentFunctionName() + ":data too large for the modulus")
+	}
+	return new(big.Int).Exp(m, big.NewInt(int64(pub.E)), pub.N).FillBytes(make([]byte, pub.Size())), nil
+}
+
+// RSAPrivateOp performs the textbook RSA private-key operation data^d mod n without any padding, like
+// openssl rsautl -decrypt -raw. It reverses RSAPublicOp. The result has the size of the key, so leading
+// zero bytes of the original data appear in the result.
+//
+// The input is blinded with a random r (data * r^e, result * r^-1), because math/big exponentiation is
+// not constant-time and would otherwise leak d through timing to whoever chooses the input. Blinding
+// reduces, but does not remove, such side channels, so data should still not be attacker-controlled.
+//
+// UNSAFE: see RSAPublicOp. Only use it for interop and debugging.
+func RSAPrivateOp(priv *rsa.PrivateKey, data []byte) ([]byte, error) {
+	if priv == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	c := new(big.Int).SetBytes(data)
+	if c.Cmp(priv.N) >= 0 {
+		return nil, errors.New(CurrentFunctionName() + ":data too large for the modulus")
+	}
+	var r, rInv *big.Int
+	for rInv == nil {
+		var err error
+		if r, err = rand.Int(rand.Reader, priv.N); err != nil {
+			return nil, errors.New(CurrentFunctionName() + ":blinding:" + err.Error())
+		}
+		if r.Sign() == 0 {
+			continue
+		}
+		rInv = new(big.Int).ModInverse(r, priv.N) // nil if r and n are not coprime
+	}
+	blinded := new(big.Int).Exp(r, big.NewInt(int64(priv.E)), priv.N)
+	blinded.Mul(blinded, c).Mod(blinded, priv.N)
+	m := new(big.Int).Exp(blinded, priv.D, priv.N)
+	m.Mul(m, rInv).Mod(m, priv.N)
+	return m.FillBytes(make([]byte, priv.Size())), nil
+}
+
+// EOF
@@ -0,0 +1,30 @@
+package go_libs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRSARawOpRoundTrip(t *testing.T) {
+	key := testPrivateKey(t)
+	data := []byte("raw")
+	ciphertext, err := RSAPublicOp(&key.PublicKey, data)
+	if err != nil {
+		t.Fatalf("RSAPublicOp failed:%s\n", err)
+	}
+	if len(ciphertext) != key.Size() {
+		t.Errorf("Ciphertext length error, is:%d, expected:%d\n", len(ciphertext), key.Size())
+	}
+	plaintext, err := RSAPrivateOp(key, ciphertext)
+	if err != nil {
+		t.Fatalf("RSAPrivateOp failed:%s\n", err)
+	}
+	if !bytes.Equal(bytes.TrimLeft(plaintext, "\x00"), data) {
+		t.Errorf("Round-trip error, is:%x, expected:%x\n", plaintext, data)
+	}
+	if _, err := RSAPublicOp(&key.PublicKey, bytes.Repeat([]byte{0xff}, key.Size())); err == nil {
+		t.Errorf("Data larger than the modulus should be rejected\n")
+	}
+}
+
+// EOF
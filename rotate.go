@@ -0,0 +1,96 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// rotationTimestampFormat is appended to the key file name to create the name of the backup.
+const rotationTimestampFormat = "20060102T150405"
+
+// linkOrCopyFile creates to as a hard link of from or, if the file system does not support hard links,
+// as a copy with the same permissions. An existing file to is never overwritten.
+func linkOrCopyFile(from, to string) error {
+	if err := os.Link(from, to); err == nil || errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		_ = os.Remove(to)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(to)
+		return err
+	}
+	return nil
+}
+
+// backupKeyPairFiles creates a backup of the private key file and, if it exists, of the public key file
+// (suffix .pub), see linkOrCopyFile. The original files stay in place. withPub reports if the public key
+// file was backed up.
+func backupKeyPairFiles(from, to string) (withPub bool, err error) {
+	if err := linkOrCopyFile(from, to); err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(from + publicKeyFileSuffix); err == nil {
+		if err := linkOrCopyFile(from+publicKeyFileSuffix, to+publicKeyFileSuffix); err != nil {
+			_ = os.Remove(to)
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// RotateKeyPairFile replaces the existing key pair outfileName and outfileName.pub by a new RSA key
+// pair of the given size, see CreateRSAKeyPairBits. The old files are kept as a backup with a timestamp
+// suffix, e.g. key.20210102T150405 and key.20210102T150405.pub. The name of the private key backup is
+// returned, so that callers can roll back. The new files are written to temporary files and renamed
+// into place, so concurrent readers always find a complete key file. If the new key cannot be written,
+// the existing files are kept and the backup is removed again.
+func RotateKeyPairFile(outfileName string, bits int) (backupName string, err error) {
+	if err := checkRSAKeyPairBits(bits); err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	if _, err := os.Stat(outfileName); err != nil {
+		return "", errors.New(CurrentFunctionName() + ":no key to rotate:" + err.Error())
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits) // before the backup, this can take a while
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":key creation:" + err.Error())
+	}
+	backupName = outfileName + "." + time.Now().Format(rotationTimestampFormat)
+	withPub, err := backupKeyPairFiles(outfileName, backupName)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":creating backup:" + err.Error())
+	}
+	if err := replaceRSAKeyPairFiles(outfileName, outfileName+publicKeyFileSuffix, privateKey); err != nil {
+		_ = os.Remove(backupName)
+		if withPub {
+			_ = os.Remove(backupName + publicKeyFileSuffix)
+		}
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error() + ":existing key kept")
+	}
+	return backupName, nil
+}
+
+// EOF
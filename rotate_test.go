@@ -0,0 +1,79 @@
+package go_libs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotateKeyPairFile(t *testing.T) {
+	kp, _ := KeyPairFromPrivateKey(testPrivateKey(t))
+	keyFile := writeTestKeyPair(t, t.TempDir(), "key", kp)
+	backupName, err := RotateKeyPairFile(keyFile, testBitSize)
+	if err != nil {
+		t.Fatalf("RotateKeyPairFile failed:%s\n", err)
+	}
+	if filepath.Dir(backupName) != filepath.Dir(keyFile) {
+		t.Errorf("Backup directory error, is:%s, expected:%s\n", filepath.Dir(backupName), filepath.Dir(keyFile))
+	}
+	backupKey, err := LoadPrivateKey(backupName)
+	if err != nil {
+		t.Fatalf("Loading the backup failed:%s\n", err)
+	}
+	if !backupKey.Equal(kp.Private) {
+		t.Errorf("Backup does not contain the old key\n")
+	}
+	if same, err := SameKeyFile(backupName, backupName+publicKeyFileSuffix); err != nil || !same {
+		t.Errorf("Backup of the public key error, same:%t, error:%v\n", same, err)
+	}
+	newKey, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		t.Fatalf("Loading the new key failed:%s\n", err)
+	}
+	if newKey.Equal(kp.Private) {
+		t.Errorf("Rotated key equals the old key\n")
+	}
+	if same, err := SameKeyFile(keyFile, keyFile+publicKeyFileSuffix); err != nil || !same {
+		t.Errorf("New public key error, same:%t, error:%v\n", same, err)
+	}
+	if _, err := RotateKeyPairFile(filepath.Join(t.TempDir(), "missing"), testBitSize); err == nil {
+		t.Errorf("Rotating a missing key should fail\n")
+	}
+	if _, err := RotateKeyPairFile(keyFile, 2049); err == nil {
+		t.Errorf("Rotating to an unsupported key size should fail\n")
+	}
+}
+
+func TestRotateKeyPairFileKeepsKeyOnError(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	kp, _ := KeyPairFromPrivateKey(testPrivateKey(t))
+	file, err := os.Create(keyFile)
+	if err != nil {
+		t.Fatalf("Create failed:%s\n", err)
+	}
+	if err := WriteRsaPrivateKey(file, kp.Private); err != nil {
+		t.Fatalf("WriteRsaPrivateKey failed:%s\n", err)
+	}
+	file.Close()
+	// a directory in place of the public key file makes replacing it fail
+	if err := os.Mkdir(keyFile+publicKeyFileSuffix, 0700); err != nil {
+		t.Fatalf("Mkdir failed:%s\n", err)
+	}
+	if _, err := RotateKeyPairFile(keyFile, testBitSize); err == nil {
+		t.Fatalf("Rotation should fail\n")
+	}
+	key, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		t.Fatalf("Loading the key failed:%s\n", err)
+	}
+	if !key.Equal(kp.Private) {
+		t.Errorf("Key should be kept after a failed rotation\n")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("Backup or temporary files left, directory has %d entries, expected:2\n", len(entries))
+	}
+}
+
+// EOF
@@ -0,0 +1,61 @@
+package go_libs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// secureEnvelopeNonceSize is the size of the random nonce embedded by SignSecureEnvelope.
+const secureEnvelopeNonceSize = 16
+
+// secureEnvelopeDomain separates the signatures of secure envelopes from the ones of SignTimestamped.
+var secureEnvelopeDomain = []byte("go_libs secure envelope v1\x00")
+
+// SignSecureEnvelope signs the message together with the current time and a random nonce, for full
+// replay protection, see VerifySecureEnvelope. The envelope has the layout of SignTimestamped for the
+// data nonce (16 bytes) || msg, but the signature also covers a fixed domain prefix. So signatures of
+// SignTimestamped are not accepted as secure envelopes and vice versa.
+func SignSecureEnvelope(key *rsa.PrivateKey, msg []byte) (envelope []byte, err error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s:Error, private %w", CurrentFunctionName(), ErrNilKey)
+	}
+	data := make([]byte, secureEnvelopeNonceSize, secureEnvelopeNonceSize+len(msg))
+	if _, err := io.ReadFull(rand.Reader, data); err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":nonce creation:" + err.Error())
+	}
+	envelope, err = signTimestamped(key, secureEnvelopeDomain, append(data, msg...))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return envelope, nil
+}
+
+// VerifySecureEnvelope verifies an envelope created by SignSecureEnvelope and returns the message.
+// Envelopes signed more than maxAge ago or more than a minute in the future and envelopes with a nonce
+// already seen by the store are rejected. The nonce is only recorded if the signature is valid and the
+// signing time is accepted. The store must remember nonces for at least maxAge plus one minute.
+func VerifySecureEnvelope(pub *rsa.PublicKey, envelope []byte, maxAge time.Duration, store NonceStore) ([]byte, error) {
+	if store == nil {
+		return nil, errors.New(CurrentFunctionName() + ":Error, nonce store is nil")
+	}
+	signingTime, data, err := verifyTimestampedRange(pub, secureEnvelopeDomain, envelope)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := checkTimestampAge(signingTime, maxAge); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if len(data) < secureEnvelopeNonceSize {
+		return nil, fmt.Errorf("%s:%w:envelope without nonce", CurrentFunctionName(), ErrParse)
+	}
+	if store.Seen(data[:secureEnvelopeNonceSize]) {
+		return nil, fmt.Errorf("%s:%w:nonce was already used, replay?", CurrentFunctionName(), ErrVerification)
+	}
+	return data[secureEnvelopeNonceSize:], nil
+}
+
+// EOF
@@ -0,0 +1,90 @@
+package go_libs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecureEnvelope(t *testing.T) {
+	key := testPrivateKey(t)
+	store := NewMemoryNonceStore()
+	msg := []byte("pay 100")
+	envelope, err := SignSecureEnvelope(key, msg)
+	if err != nil {
+		t.Fatalf("SignSecureEnvelope failed:%s\n", err)
+	}
+	verified, err := VerifySecureEnvelope(&key.PublicKey, envelope, time.Minute, store)
+	if err != nil {
+		t.Fatalf("VerifySecureEnvelope failed:%s\n", err)
+	}
+	if !bytes.Equal(verified, msg) {
+		t.Errorf("Message error, is:%s, expected:%s\n", verified, msg)
+	}
+	if _, err := VerifySecureEnvelope(&key.PublicKey, envelope, time.Minute, store); err == nil || !strings.Contains(err.Error(), "nonce") {
+		t.Errorf("Reused nonce error, is:%v, expected a replay error\n", err)
+	}
+	second, err := SignSecureEnvelope(key, msg)
+	if err != nil {
+		t.Fatalf("SignSecureEnvelope failed:%s\n", err)
+	}
+	if _, err := VerifySecureEnvelope(&key.PublicKey, second, time.Minute, store); err != nil {
+		t.Errorf("Same message with a new nonce should verify:%s\n", err)
+	}
+	if _, err := VerifySecureEnvelope(&key.PublicKey, second, time.Minute, nil); err == nil || errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil store error, is:%v, expected an error not wrapping %v\n", err, ErrNilKey)
+	}
+}
+
+func TestSecureEnvelopeStale(t *testing.T) {
+	key := testPrivateKey(t)
+	timestampClock = func() time.Time { return time.Now().Add(-time.Hour) }
+	envelope, err := SignSecureEnvelope(key, []byte("old message"))
+	timestampClock = time.Now
+	if err != nil {
+		t.Fatalf("SignSecureEnvelope failed:%s\n", err)
+	}
+	store := NewMemoryNonceStore()
+	if _, err := VerifySecureEnvelope(&key.PublicKey, envelope, time.Minute, store); err == nil || !strings.Contains(err.Error(), "too old") {
+		t.Errorf("Stale envelope error, is:%v, expected a too old error\n", err)
+	}
+	if _, err := VerifySecureEnvelope(&key.PublicKey, envelope, 2*time.Hour, store); err != nil {
+		t.Errorf("Nonce of the rejected stale envelope should not be recorded:%s\n", err)
+	}
+}
+
+func TestSecureEnvelopeFuture(t *testing.T) {
+	key := testPrivateKey(t)
+	timestampClock = func() time.Time { return time.Now().Add(time.Hour) }
+	envelope, err := SignSecureEnvelope(key, []byte("future message"))
+	timestampClock = time.Now
+	if err != nil {
+		t.Fatalf("SignSecureEnvelope failed:%s\n", err)
+	}
+	if _, err := VerifySecureEnvelope(&key.PublicKey, envelope, time.Minute, NewMemoryNonceStore()); !errors.Is(err, ErrVerification) || !strings.Contains(err.Error(), "future") {
+		t.Errorf("Future envelope error, is:%v, expected a future error wrapping %v\n", err, ErrVerification)
+	}
+}
+
+func TestSecureEnvelopeDomain(t *testing.T) {
+	key := testPrivateKey(t)
+	data := append(bytes.Repeat([]byte{0x01}, secureEnvelopeNonceSize), "pay 100"...)
+	signed, err := SignTimestamped(key, data)
+	if err != nil {
+		t.Fatalf("SignTimestamped failed:%s\n", err)
+	}
+	if _, err := VerifySecureEnvelope(&key.PublicKey, signed, time.Minute, NewMemoryNonceStore()); !errors.Is(err, ErrVerification) {
+		t.Errorf("Plain timestamped signature error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+	envelope, err := SignSecureEnvelope(key, []byte("pay 100"))
+	if err != nil {
+		t.Fatalf("SignSecureEnvelope failed:%s\n", err)
+	}
+	if _, err := VerifyTimestamped(&key.PublicKey, envelope, time.Minute); !errors.Is(err, ErrVerification) {
+		t.Errorf("Envelope as timestamped signature error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+}
+
+// EOF
@@ -0,0 +1,55 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// signatureEnvelope is the JSON format {alg, digest_alg, signature_b64} used by some partners to
+// transport a detached signature.
+type signatureEnvelope struct {
+	Alg          string `json:"alg"`
+	DigestAlg    string `json:"digest_alg"`
+	SignatureB64 string `json:"signature_b64"`
+}
+
+// VerifyEnvelopeJSON verifies msg against a signature transported in a JSON envelope of the form
+// {"alg":"PS256","digest_alg":"SHA-256","signature_b64":"..."}. The alg prefix selects the scheme,
+// PS for RSA-PSS and RS for RSA PKCS#1 v1.5. The digest_alg selects the hash; it must match the
+// hash size given in alg. If no error is returned, the verification was successful.
+func VerifyEnvelopeJSON(pub *rsa.PublicKey, msg []byte, envelopeJSON []byte) error {
+	if pub == nil {
+		return fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	var env signatureEnvelope
+	if err := json.Unmarshal(envelopeJSON, &env); err != nil {
+		return fmt.Errorf("%s:%w:parsing envelope:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	hash, err := hashByName(env.DigestAlg)
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if len(env.Alg) < 3 || env.Alg[2:] != strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(env.DigestAlg), "SHA"), "-") {
+		return fmt.Errorf("%s:%w:alg %s does not match digest_alg %s", CurrentFunctionName(), ErrParse, env.Alg, env.DigestAlg)
+	}
+	sig, err := base64.StdEncoding.DecodeString(env.SignatureB64)
+	if err != nil {
+		return fmt.Errorf("%s:%w:Error, decoding base64 string", CurrentFunctionName(), ErrParse)
+	}
+	h := hash.New()
+	h.Write(msg)
+	digest := h.Sum(nil)
+	switch env.Alg[:2] {
+	case "PS":
+		return rsa.VerifyPSS(pub, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	case "RS":
+		return rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+	default:
+		return fmt.Errorf("%s:%w:unsupported alg %s", CurrentFunctionName(), ErrParse, env.Alg)
+	}
+}
+
+// EOF
@@ -0,0 +1,52 @@
+package go_libs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+)
+
+func envelopeJSON(alg string, digestAlg string, sig []byte) []byte {
+	return []byte(`{"alg":"` + alg + `","digest_alg":"` + digestAlg + `","signature_b64":"` +
+		base64.StdEncoding.EncodeToString(sig) + `"}`)
+}
+
+func TestVerifyEnvelopeJSONPS256(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("envelope message")
+	digest := sha256.Sum256(msg)
+	sig, err := rsa.SignPSS(rand.Reader, key, crypto.SHA256, digest[:], nil)
+	if err != nil {
+		t.Fatalf("SignPSS failed:%s\n", err)
+	}
+	if err := VerifyEnvelopeJSON(&key.PublicKey, msg, envelopeJSON("PS256", "SHA-256", sig)); err != nil {
+		t.Errorf("PS256 envelope verification failed:%s\n", err)
+	}
+	if err := VerifyEnvelopeJSON(&key.PublicKey, msg, envelopeJSON("RS256", "SHA-256", sig)); err == nil {
+		t.Errorf("PSS signature must not verify as RS256\n")
+	}
+}
+
+func TestVerifyEnvelopeJSONRS256(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("envelope message")
+	digest := sha256.Sum256(msg)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15 failed:%s\n", err)
+	}
+	if err := VerifyEnvelopeJSON(&key.PublicKey, msg, envelopeJSON("RS256", "SHA-256", sig)); err != nil {
+		t.Errorf("RS256 envelope verification failed:%s\n", err)
+	}
+	if err := VerifyEnvelopeJSON(&key.PublicKey, []byte("tampered"), envelopeJSON("RS256", "SHA-256", sig)); err == nil {
+		t.Errorf("Verification of a tampered message should fail\n")
+	}
+	if err := VerifyEnvelopeJSON(&key.PublicKey, msg, envelopeJSON("RS512", "SHA-256", sig)); err == nil {
+		t.Errorf("Mismatching alg and digest_alg should be rejected\n")
+	}
+}
+
+// EOF
@@ -0,0 +1,89 @@
+package go_libs
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// PublicKeyToAuthorizedKey returns the public key in the single-line OpenSSH authorized_keys format,
+// e.g. ssh-rsa AAAA... comment, terminated by a newline. The comment is optional and must not contain
+// line breaks.
+func PublicKeyToAuthorizedKey(pub *rsa.PublicKey, comment string) (string, error) {
+	if pub == nil {
+		return "", fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+	}
+	if strings.ContainsAny(comment, "\r\n") {
+		return "", errors.New(CurrentFunctionName() + ":comment must not contain line breaks")
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(sshPub)), "\n")
+	if comment != "" {
+		line += " " + comment
+	}
+	return line + "\n", nil
+}
+
+// rsaFromSSHKey asserts that the private key returned by the ssh package is an RSA key.
+func rsaFromSSHKey(key interface{}) (*rsa.PrivateKey, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		k.Precompute()
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return nil, errors.New("SSH key is an ECDSA key, not RSA")
+	case *ed25519.PrivateKey, ed25519.PrivateKey:
+		return nil, errors.New("SSH key is an Ed25519 key, not RSA")
+	default:
+		return nil, fmt.Errorf("unsupported SSH key type %T, not RSA", key)
+	}
+}
+
+// ParseOpenSSHPrivateKey parses an unencrypted RSA private key in the OPENSSH PRIVATE KEY format as
+// generated by ssh-keygen, so that existing SSH keys can be used for signing. PKCS#1 and PKCS#8 PEM
+// blocks are also accepted. ECDSA and Ed25519 SSH keys are rejected. Use
+// ParseOpenSSHPrivateKeyWithPassphrase for encrypted keys.
+func ParseOpenSSHPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	key, err := ssh.ParseRawPrivateKey(der)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("%s:%w:key is encrypted, a passphrase is required, see ParseOpenSSHPrivateKeyWithPassphrase",
+				CurrentFunctionName(), ErrParse)
+		}
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	priv, err := rsaFromSSHKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	return priv, nil
+}
+
+// ParseOpenSSHPrivateKeyWithPassphrase works like ParseOpenSSHPrivateKey for a passphrase-protected key.
+// If the passphrase is wrong, the returned error wraps ErrWrongPassword.
+func ParseOpenSSHPrivateKeyWithPassphrase(der []byte, passphrase string) (*rsa.PrivateKey, error) {
+	key, err := ssh.ParseRawPrivateKeyWithPassphrase(der, []byte(passphrase))
+	if err != nil {
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), ErrWrongPassword)
+		}
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	priv, err := rsaFromSSHKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	return priv, nil
+}
+
+// EOF
@@ -0,0 +1,83 @@
+package go_libs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestPublicKeyToAuthorizedKey(t *testing.T) {
+	key := testPrivateKey(t)
+	line, err := PublicKeyToAuthorizedKey(&key.PublicKey, "deploy@host")
+	if err != nil {
+		t.Fatalf("PublicKeyToAuthorizedKey failed:%s\n", err)
+	}
+	if !strings.HasPrefix(line, "ssh-rsa AAAA") || strings.Count(line, "\n") != 1 {
+		t.Errorf("Authorized key format error, is:%s\n", line)
+	}
+	parsed, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		t.Fatalf("ParseAuthorizedKey failed:%s\n", err)
+	}
+	if comment != "deploy@host" {
+		t.Errorf("Comment error, is:%s, expected:%s\n", comment, "deploy@host")
+	}
+	cryptoPub, ok := parsed.(ssh.CryptoPublicKey)
+	if !ok || !key.PublicKey.Equal(cryptoPub.CryptoPublicKey()) {
+		t.Errorf("Parsed authorized key differs from the public key\n")
+	}
+	if _, err := PublicKeyToAuthorizedKey(&key.PublicKey, "two\nlines"); err == nil {
+		t.Errorf("Comment with line break should be rejected\n")
+	}
+	if _, err := PublicKeyToAuthorizedKey(nil, ""); !errors.Is(err, ErrNilKey) {
+		t.Errorf("Nil key error, is:%v, expected:%v\n", err, ErrNilKey)
+	}
+}
+
+func TestParseOpenSSHPrivateKey(t *testing.T) {
+	key := testPrivateKey(t)
+	block, err := ssh.MarshalPrivateKey(key, "test")
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey failed:%s\n", err)
+	}
+	priv, err := ParseOpenSSHPrivateKey(pem.EncodeToMemory(block))
+	if err != nil {
+		t.Fatalf("ParseOpenSSHPrivateKey failed:%s\n", err)
+	}
+	if !priv.Equal(key) {
+		t.Errorf("Parsed SSH key differs from the original key\n")
+	}
+
+	block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "test", []byte("secret"))
+	if err != nil {
+		t.Fatalf("MarshalPrivateKeyWithPassphrase failed:%s\n", err)
+	}
+	encrypted := pem.EncodeToMemory(block)
+	if _, err := ParseOpenSSHPrivateKey(encrypted); err == nil || !strings.Contains(err.Error(), "passphrase is required") {
+		t.Errorf("Encrypted key error, is:%v, expected a missing passphrase error\n", err)
+	}
+	if _, err := ParseOpenSSHPrivateKeyWithPassphrase(encrypted, "wrong"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Wrong passphrase error, is:%v, expected:%v\n", err, ErrWrongPassword)
+	}
+	if priv, err = ParseOpenSSHPrivateKeyWithPassphrase(encrypted, "secret"); err != nil || !priv.Equal(key) {
+		t.Errorf("ParseOpenSSHPrivateKeyWithPassphrase failed:%v\n", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey failed:%s\n", err)
+	}
+	if block, err = ssh.MarshalPrivateKey(edKey, "ed"); err != nil {
+		t.Fatalf("MarshalPrivateKey failed:%s\n", err)
+	}
+	if _, err := ParseOpenSSHPrivateKey(pem.EncodeToMemory(block)); !errors.Is(err, ErrParse) || !strings.Contains(err.Error(), "Ed25519") {
+		t.Errorf("Ed25519 key error, is:%v, expected an error naming Ed25519\n", err)
+	}
+}
+
+// EOF
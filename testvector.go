@@ -0,0 +1,45 @@
+package go_libs
+
+import (
+	"errors"
+)
+
+// testVectorMessage is the sample message signed by GenerateTestVector.
+const testVectorMessage = `{"id":4711,"msg":"go_libs test vector"}`
+
+// TestVector is an interop fixture for projects testing against this package: a key-pair, a sample
+// message, and its signature.
+type TestVector struct {
+	PrivateKeyPEM string // PKCS#1 PEM of the private key
+	PublicKeyPEM  string // PKIX PEM of the public key
+	Algorithm     string // signature algorithm, RSA-PSS with SHA-256 as created by SignPSSByteArray
+	Message       []byte
+	Signature     []byte
+}
+
+// GenerateTestVector creates a TestVector with a new key-pair of the given size (at least 2048 bits).
+// The signature of the message verifies with VerifyPSSByteArray and the public key.
+func GenerateTestVector(bits int) (TestVector, error) {
+	privPEM, pubPEM, err := CreateRSAKeyPairPEMStrings(bits)
+	if err != nil {
+		return TestVector{}, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	priv, err := Pem2RsaPrivateKey([]byte(privPEM))
+	if err != nil {
+		return TestVector{}, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	msg := []byte(testVectorMessage)
+	sig, err := SignPSSByteArray(priv, Sha256bytes2bytes(msg))
+	if err != nil {
+		return TestVector{}, errors.New(CurrentFunctionName() + ":" + err.Error())
+	}
+	return TestVector{
+		PrivateKeyPEM: privPEM,
+		PublicKeyPEM:  pubPEM,
+		Algorithm:     AlgorithmRSAPSSSHA256,
+		Message:       msg,
+		Signature:     sig,
+	}, nil
+}
+
+// EOF
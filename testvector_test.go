@@ -0,0 +1,28 @@
+package go_libs
+
+import (
+	"testing"
+)
+
+func TestGenerateTestVector(t *testing.T) {
+	vector, err := GenerateTestVector(testBitSize)
+	if err != nil {
+		t.Fatalf("GenerateTestVector failed:%s\n", err)
+	}
+	pub, err := Pem2RsaPublicKey([]byte(vector.PublicKeyPEM))
+	if err != nil {
+		t.Fatalf("Pem2RsaPublicKey failed:%s\n", err)
+	}
+	if err := VerifyPSSByteArray(pub, vector.Signature, vector.Message); err != nil {
+		t.Errorf("Signature of the test vector does not verify:%s\n", err)
+	}
+	priv, err := Pem2RsaPrivateKey([]byte(vector.PrivateKeyPEM))
+	if err != nil {
+		t.Fatalf("Pem2RsaPrivateKey failed:%s\n", err)
+	}
+	if !priv.PublicKey.Equal(pub) {
+		t.Errorf("Private and public key of the test vector do not match\n")
+	}
+}
+
+// EOF
@@ -0,0 +1,110 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// timestampSize is the size of the big-endian Unix time in nanoseconds prepended by SignTimestamped.
+const timestampSize = 8
+
+// timestampClock returns the signing time used by SignTimestamped. It can be replaced by tests.
+var timestampClock = time.Now
+
+// timestampClockSkew is the tolerance of VerifyTimestamped for signing times in the future, e.g. if the
+// clocks of the signer and the verifier are not synchronised.
+const timestampClockSkew = time.Minute
+
+// SignTimestamped signs the message together with the current time, so that verifiers can reject old
+// messages. The result is: Unix time in ns (8 bytes, big-endian) || msg || RSA-PSS signature
+func SignTimestamped(key *rsa.PrivateKey, msg []byte) ([]byte, error) {
+	signed, err := signTimestamped(key, nil, msg)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return signed, nil
+}
+
+// signTimestamped implements SignTimestamped. The domain is signed in front of the timestamp, but it is
+// not part of the result. Formats built on SignTimestamped use it, so that their signatures cannot be
+// confused with the ones of SignTimestamped. The timestamp cannot be chosen by the caller, so no message
+// signed by SignTimestamped starts with a domain.
+func signTimestamped(key *rsa.PrivateKey, domain, msg []byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("Error, private %w", ErrNilKey)
+	}
+	signed := make([]byte, timestampSize, timestampSize+len(msg)+key.Size())
+	binary.BigEndian.PutUint64(signed, uint64(timestampClock().UnixNano()))
+	signed = append(signed, msg...)
+	sig, err := SignPSSByteArray(key, timestampedDigest(domain, signed))
+	if err != nil {
+		return nil, err
+	}
+	return append(signed, sig...), nil
+}
+
+// timestampedDigest returns the SHA-256 digest of domain || signed, see signTimestamped.
+func timestampedDigest(domain, signed []byte) []byte {
+	msgHash := sha256.New()
+	_, _ = msgHash.Write(domain)
+	_, _ = msgHash.Write(signed)
+	return msgHash.Sum(nil)
+}
+
+// VerifyTimestampedRange verifies a message signed by SignTimestamped and returns the embedded signing
+// time and the message. The age is not checked, so callers can apply their own policy.
+func VerifyTimestampedRange(pub *rsa.PublicKey, signed []byte) (time.Time, []byte, error) {
+	signingTime, msg, err := verifyTimestampedRange(pub, nil, signed)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return signingTime, msg, nil
+}
+
+// verifyTimestampedRange implements VerifyTimestampedRange for messages signed by signTimestamped with
+// the domain.
+func verifyTimestampedRange(pub *rsa.PublicKey, domain, signed []byte) (time.Time, []byte, error) {
+	if pub == nil {
+		return time.Time{}, nil, fmt.Errorf("Error, public %w", ErrNilKey)
+	}
+	if len(signed) < timestampSize+pub.Size() {
+		return time.Time{}, nil, fmt.Errorf("%w:signed message too short", ErrParse)
+	}
+	data, sig := signed[:len(signed)-pub.Size()], signed[len(signed)-pub.Size():]
+	if err := VerifyDigestSignature(pub, timestampedDigest(domain, data), sig); err != nil {
+		return time.Time{}, nil, fmt.Errorf("signature verification:%w", err)
+	}
+	signingTime := time.Unix(0, int64(binary.BigEndian.Uint64(data)))
+	return signingTime, data[timestampSize:], nil
+}
+
+// VerifyTimestamped verifies a message signed by SignTimestamped and returns the message. Messages
+// signed more than maxAge ago or more than a minute in the future are rejected.
+func VerifyTimestamped(pub *rsa.PublicKey, signed []byte, maxAge time.Duration) ([]byte, error) {
+	signingTime, msg, err := verifyTimestampedRange(pub, nil, signed)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	if err := checkTimestampAge(signingTime, maxAge); err != nil {
+		return nil, fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	return msg, nil
+}
+
+// checkTimestampAge returns an error wrapping ErrVerification if the signing time is more than maxAge
+// ago or more than timestampClockSkew in the future.
+func checkTimestampAge(signingTime time.Time, maxAge time.Duration) error {
+	now := time.Now()
+	if now.Sub(signingTime) > maxAge {
+		return fmt.Errorf("%w:signature too old, signed at %s", ErrVerification, signingTime.UTC().Format(time.RFC3339))
+	}
+	if signingTime.Sub(now) > timestampClockSkew {
+		return fmt.Errorf("%w:signature from the future, signed at %s", ErrVerification, signingTime.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// EOF
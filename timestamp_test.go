@@ -0,0 +1,57 @@
+package go_libs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyTimestampedRange(t *testing.T) {
+	key := testPrivateKey(t)
+	signingTime := time.Date(2021, 6, 1, 12, 30, 0, 123, time.UTC)
+	timestampClock = func() time.Time { return signingTime }
+	defer func() { timestampClock = time.Now }()
+
+	msg := []byte("timestamped message")
+	signed, err := SignTimestamped(key, msg)
+	if err != nil {
+		t.Fatalf("SignTimestamped failed:%s\n", err)
+	}
+	ts, verified, err := VerifyTimestampedRange(&key.PublicKey, signed)
+	if err != nil {
+		t.Fatalf("VerifyTimestampedRange failed:%s\n", err)
+	}
+	if !ts.Equal(signingTime) {
+		t.Errorf("Signing time error, is:%s, expected:%s\n", ts, signingTime)
+	}
+	if !bytes.Equal(verified, msg) {
+		t.Errorf("Message error, is:%s, expected:%s\n", verified, msg)
+	}
+	if _, err := VerifyTimestamped(&key.PublicKey, signed, time.Hour); err == nil {
+		t.Errorf("VerifyTimestamped should reject the old signature\n")
+	}
+}
+
+func TestVerifyTimestampedFuture(t *testing.T) {
+	key := testPrivateKey(t)
+	defer func() { timestampClock = time.Now }()
+	msg := []byte("timestamped message")
+	timestampClock = func() time.Time { return time.Now().Add(30 * time.Second) }
+	signed, err := SignTimestamped(key, msg)
+	if err != nil {
+		t.Fatalf("SignTimestamped failed:%s\n", err)
+	}
+	if _, err := VerifyTimestamped(&key.PublicKey, signed, time.Minute); err != nil {
+		t.Errorf("Signing time within the clock skew should be accepted:%s\n", err)
+	}
+	timestampClock = func() time.Time { return time.Now().Add(time.Hour) }
+	if signed, err = SignTimestamped(key, msg); err != nil {
+		t.Fatalf("SignTimestamped failed:%s\n", err)
+	}
+	if _, err := VerifyTimestamped(&key.PublicKey, signed, 2*time.Hour); !errors.Is(err, ErrVerification) {
+		t.Errorf("Future signing time error, is:%v, expected:%v\n", err, ErrVerification)
+	}
+}
+
+// EOF
@@ -0,0 +1,88 @@
+package go_libs
+
+import (
+	"container/list"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// verifyCacheKey is the SHA-256 digest of the (fingerprint, msg, sig) tuple of a successful verification.
+type verifyCacheKey [sha256.Size]byte
+
+// VerifyCache memoizes successful RSA-PSS verifications, see VerifyPSSByteArray. Repeated verifications
+// of the same key, message, and signature, e.g. by webhook retries, skip the RSA operation. Only
+// successes are cached. The cache is a bounded LRU and safe for concurrent use.
+type VerifyCache struct {
+	size    int
+	mutex   sync.Mutex
+	order   *list.List // of verifyCacheKey, most recently used first
+	entries map[verifyCacheKey]*list.Element
+	verify  func(pub *rsa.PublicKey, sig []byte, msg []byte) error // replaceable for tests
+}
+
+// NewVerifyCache creates a VerifyCache which remembers up to size successful verifications. A size
+// smaller than 1 is treated as 1.
+func NewVerifyCache(size int) *VerifyCache {
+	if size < 1 {
+		size = 1
+	}
+	return &VerifyCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[verifyCacheKey]*list.Element),
+		verify:  VerifyPSSByteArray,
+	}
+}
+
+// verifyCacheKeyOf creates the cache key. The fields are length-prefixed to keep their boundaries.
+func verifyCacheKeyOf(fingerprint string, msg []byte, sig []byte) verifyCacheKey {
+	h := sha256.New()
+	var length [8]byte
+	for _, field := range [][]byte{[]byte(fingerprint), msg, sig} {
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		h.Write(length[:])
+		h.Write(field)
+	}
+	var key verifyCacheKey
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// Verify verifies the signature of msg like VerifyPSSByteArray. If the same tuple was verified
+// successfully before, the cached result is returned. If no error is returned, the verification was
+// successful.
+func (c *VerifyCache) Verify(pub *rsa.PublicKey, msg []byte, sig []byte) error {
+	fingerprint, err := PublicKeyFingerprint(pub)
+	if err != nil {
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+	key := verifyCacheKeyOf(fingerprint, msg, sig)
+	c.mutex.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		c.mutex.Unlock()
+		return nil
+	}
+	c.mutex.Unlock()
+
+	if err := c.verify(pub, sig, msg); err != nil { // outside of the lock, this can take a while
+		return fmt.Errorf("%s:%w", CurrentFunctionName(), err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.order.PushFront(key)
+		if c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(verifyCacheKey))
+		}
+	}
+	return nil
+}
+
+// EOF
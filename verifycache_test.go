@@ -0,0 +1,58 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"testing"
+)
+
+func TestVerifyCache(t *testing.T) {
+	key := testPrivateKey(t)
+	cache := NewVerifyCache(2)
+	verifications := 0
+	cache.verify = func(pub *rsa.PublicKey, sig []byte, msg []byte) error {
+		verifications++
+		return VerifyPSSByteArray(pub, sig, msg)
+	}
+	sign := func(msg string) []byte {
+		sig, err := SignPSSByteArray(key, Sha256bytes2bytes([]byte(msg)))
+		if err != nil {
+			t.Fatalf("SignPSSByteArray failed:%s\n", err)
+		}
+		return sig
+	}
+
+	sig := sign("webhook")
+	for i := 0; i < 3; i++ {
+		if err := cache.Verify(&key.PublicKey, []byte("webhook"), sig); err != nil {
+			t.Fatalf("Verify %d failed:%s\n", i, err)
+		}
+	}
+	if verifications != 1 {
+		t.Errorf("Verification count error, is:%d, expected:%d\n", verifications, 1)
+	}
+
+	// failures are not cached
+	for i := 0; i < 2; i++ {
+		if err := cache.Verify(&key.PublicKey, []byte("modified"), sig); err == nil {
+			t.Errorf("Verification of a modified message should fail\n")
+		}
+	}
+	if verifications != 3 {
+		t.Errorf("Verification count after failures error, is:%d, expected:%d\n", verifications, 3)
+	}
+
+	// the least recently used entry is evicted
+	for _, msg := range []string{"second", "third"} {
+		if err := cache.Verify(&key.PublicKey, []byte(msg), sign(msg)); err != nil {
+			t.Fatalf("Verify failed:%s\n", err)
+		}
+	}
+	if err := cache.Verify(&key.PublicKey, []byte("webhook"), sig); err != nil {
+		t.Fatalf("Verify failed:%s\n", err)
+	}
+	if verifications != 6 {
+		t.Errorf("Verification count after eviction error, is:%d, expected:%d\n", verifications, 6)
+	}
+}
+
+// EOF
@@ -0,0 +1,124 @@
+package go_libs
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// mgf1SHA256 is the mask generation function MGF1 of PKCS#1 with SHA-256.
+func mgf1SHA256(seed []byte, length int) []byte {
+	var mask []byte
+	var counter [4]byte
+	for i := uint32(0); len(mask) < length; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h := sha256.New()
+		h.Write(seed)
+		h.Write(counter[:])
+		mask = h.Sum(mask)
+	}
+	return mask[:length]
+}
+
+// pssDigestMismatch decodes the RSA-PSS (SHA-256) encoded message of the signature. It returns true
+// if the encoding is well-formed, i.e. the signature was created with the private key of pub, but
+// for a different message digest.
+func pssDigestMismatch(pub *rsa.PublicKey, sig []byte, msgDigest []byte) bool {
+	emBits := pub.N.BitLen() - 1
+	emLen := (emBits + 7) / 8
+	hLen := sha256.Size
+	m := new(big.Int).Exp(new(big.Int).SetBytes(sig), big.NewInt(int64(pub.E)), pub.N)
+	if m.BitLen() > emBits || emLen < hLen+2 {
+		return false
+	}
+	em := m.FillBytes(make([]byte, emLen))
+	if em[emLen-1] != 0xbc {
+		return false
+	}
+	maskedDB, h := em[:emLen-hLen-1], em[emLen-hLen-1:emLen-1]
+	db := mgf1SHA256(h, len(maskedDB))
+	for i := range db {
+		db[i] ^= maskedDB[i]
+	}
+	db[0] &= byte(0xff >> (8*emLen - emBits))
+	separator := bytes.IndexByte(db, 0x01)
+	if separator < 0 || !bytes.Equal(db[:separator], make([]byte, separator)) {
+		return false
+	}
+	salt := db[separator+1:]
+	mPrime := sha256.New()
+	mPrime.Write(make([]byte, 8))
+	mPrime.Write(msgDigest)
+	mPrime.Write(salt)
+	return !bytes.Equal(mPrime.Sum(nil), h)
+}
+
+// VerificationResult is the outcome of VerifyDetailed.
+type VerificationResult struct {
+	Valid            bool   // true if the signature is valid for the message
+	RecomputedDigest []byte // SHA-256 digest of the message, nil if the message was not hashed
+	Reason           string // reason of a failure, empty if Valid
+	Err              error  // error of a failure wrapping ErrNilKey, ErrParse, or ErrVerification, nil if Valid
+}
+
+// failed returns the result with Reason and Err set.
+func (r VerificationResult) failed(fn string, sentinel error, reason string) VerificationResult {
+	r.Reason = reason
+	r.Err = fmt.Errorf("%s:%w:%s", fn, sentinel, reason)
+	return r
+}
+
+// VerifyDetailed verifies an RSA-PSS (SHA-256) signature of the message like VerifyPSSByteArray, but
+// returns the recomputed digest for logging or auditing and the reason of a failure:
+//   - public key is nil (ErrNilKey)
+//   - signature is nil (ErrParse)
+//   - signature length mismatch: the signature does not have the size of the key
+//   - digest mismatch: the signature was created with the matching private key, but for a different
+//     message. This implies that the message was tampered with.
+//   - verification failed: the signature was not created with the matching private key or is corrupted
+func VerifyDetailed(key *rsa.PublicKey, sig []byte, msg []byte) VerificationResult {
+	var result VerificationResult
+	if key == nil {
+		result.Reason = "public key is nil"
+		result.Err = fmt.Errorf("%s:Error, public %w", CurrentFunctionName(), ErrNilKey)
+		return result
+	}
+	if sig == nil {
+		return result.failed(CurrentFunctionName(), ErrParse, "signature is nil")
+	}
+	result.RecomputedDigest = Sha256bytes2bytes(msg)
+	if len(sig) != key.Size() {
+		return result.failed(CurrentFunctionName(), ErrVerification,
+			fmt.Sprintf("signature length mismatch, is %d bytes, expected %d bytes", len(sig), key.Size()))
+	}
+	if VerifyDigestSignature(key, result.RecomputedDigest, sig) == nil {
+		result.Valid = true
+		return result
+	}
+	if pssDigestMismatch(key, sig, result.RecomputedDigest) {
+		return result.failed(CurrentFunctionName(), ErrVerification, "digest mismatch, message was tampered with")
+	}
+	return result.failed(CurrentFunctionName(), ErrVerification, "verification failed, wrong key or corrupted signature")
+}
+
+// VerifyDetailedBase64String works like VerifyDetailed, but accepts a base64 encoded signature like
+// VerifyPSSBase64String. An invalid encoding is reported as a failure wrapping ErrParse.
+func VerifyDetailedBase64String(key *rsa.PublicKey, b64 string, msg string) VerificationResult {
+	sig, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return VerificationResult{}.failed(CurrentFunctionName(), ErrParse, "decoding base64 string")
+	}
+	return VerifyDetailed(key, sig, []byte(msg))
+}
+
+// VerifyByteArrayDetailed returns the error of VerifyDetailed. It is the same as VerifyPSSByteArray.
+// All verification errors wrap ErrVerification.
+func VerifyByteArrayDetailed(key *rsa.PublicKey, sig []byte, msg []byte) error {
+	return VerifyDetailed(key, sig, msg).Err
+}
+
+// EOF
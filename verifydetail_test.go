@@ -0,0 +1,70 @@
+package go_libs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerifyByteArrayDetailed(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("detailed verification")
+	sig, err := SignPSSByteArray(key, Sha256bytes2bytes(msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArray failed:%s\n", err)
+	}
+	if err := VerifyByteArrayDetailed(&key.PublicKey, sig, msg); err != nil {
+		t.Errorf("Valid signature failed:%s\n", err)
+	}
+	corrupted := append([]byte{}, sig...)
+	corrupted[10] ^= 0x01
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"wrong length", VerifyByteArrayDetailed(&key.PublicKey, sig[1:], msg), "signature length mismatch"},
+		{"tampered message", VerifyByteArrayDetailed(&key.PublicKey, sig, []byte("tampered")), "digest mismatch"},
+		{"corrupted signature", VerifyByteArrayDetailed(&key.PublicKey, corrupted, msg), "verification failed"},
+	} {
+		if tc.err == nil || !strings.Contains(tc.err.Error(), tc.expected) {
+			t.Errorf("Error for %s, is:%v, expected to contain:%s\n", tc.name, tc.err, tc.expected)
+		}
+		if !errors.Is(tc.err, ErrVerification) {
+			t.Errorf("Error for %s does not wrap ErrVerification\n", tc.name)
+		}
+	}
+}
+
+func TestVerifyDetailed(t *testing.T) {
+	key := testPrivateKey(t)
+	msg := []byte("audited message")
+	b64, err := SignPSSByteArray2Base64(key, Sha256bytes2bytes(msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArray2Base64 failed:%s\n", err)
+	}
+	result := VerifyDetailedBase64String(&key.PublicKey, b64, string(msg))
+	if !result.Valid || result.Err != nil || result.Reason != "" {
+		t.Errorf("Valid signature error, is:%+v\n", result)
+	}
+	if !bytes.Equal(result.RecomputedDigest, Sha256bytes2bytes(msg)) {
+		t.Errorf("Recomputed digest error, is:%x, expected:%x\n", result.RecomputedDigest, Sha256bytes2bytes(msg))
+	}
+	for _, tc := range []struct {
+		name     string
+		result   VerificationResult
+		sentinel error
+	}{
+		{"nil key", VerifyDetailedBase64String(nil, b64, string(msg)), ErrNilKey},
+		{"bad base64", VerifyDetailedBase64String(&key.PublicKey, "%%%", string(msg)), ErrParse},
+		{"nil signature", VerifyDetailed(&key.PublicKey, nil, msg), ErrParse},
+		{"tampered message", VerifyDetailedBase64String(&key.PublicKey, b64, "tampered"), ErrVerification},
+	} {
+		if tc.result.Valid || tc.result.Reason == "" || !errors.Is(tc.result.Err, tc.sentinel) {
+			t.Errorf("Result for %s, is:%+v, expected an error wrapping:%v\n", tc.name, tc.result, tc.sentinel)
+		}
+	}
+}
+
+// EOF
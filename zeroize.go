@@ -0,0 +1,70 @@
+package go_libs
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// zeroBytes overwrites the buffer with zeros, e.g. to remove key material from memory.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// zeroBigInt overwrites the words of the integer with zeros and sets it to 0.
+func zeroBigInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+// ZeroizePrivateKey overwrites the private parts of the key with zeros: the private exponent D, the
+// primes, and the precomputed CRT values. The public key N and E is kept, so the key can still be used
+// to verify, but not to sign or decrypt anymore. This is a best-effort defense for long-running daemons.
+// Copies made by the garbage collector when growing big.Int values and internal caches of crypto/rsa,
+// which are not reachable from the outside, are not wiped.
+func ZeroizePrivateKey(priv *rsa.PrivateKey) {
+	if priv == nil {
+		return
+	}
+	zeroBigInt(priv.D)
+	for _, prime := range priv.Primes {
+		zeroBigInt(prime)
+	}
+	zeroBigInt(priv.Precomputed.Dp)
+	zeroBigInt(priv.Precomputed.Dq)
+	zeroBigInt(priv.Precomputed.Qinv)
+	for i := range priv.Precomputed.CRTValues {
+		zeroBigInt(priv.Precomputed.CRTValues[i].Exp)
+		zeroBigInt(priv.Precomputed.CRTValues[i].Coeff)
+		zeroBigInt(priv.Precomputed.CRTValues[i].R)
+	}
+	priv.Precomputed = rsa.PrecomputedValues{}
+}
+
+// LoadPrivateKeySecure works like LoadPrivateKey, but overwrites the PEM buffer and the decoded DER with
+// zeros before returning. This reduces the time in which raw key bytes sit in memory until they are
+// garbage collected. The returned key itself still contains the key material.
+func LoadPrivateKeySecure(filename string) (*rsa.PrivateKey, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.New(CurrentFunctionName() + ":reading file:" + err.Error())
+	}
+	defer zeroBytes(buf)
+	priv, _, err := pem2RsaPrivateKeyWithType(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w:%s", CurrentFunctionName(), ErrParse, err.Error())
+	}
+	return priv, nil
+}
+
+// EOF
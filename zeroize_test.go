@@ -0,0 +1,56 @@
+package go_libs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPrivateKeySecure(t *testing.T) {
+	kp, _ := KeyPairFromPrivateKey(testPrivateKey(t))
+	keyFile := writeTestKeyPair(t, t.TempDir(), "key", kp)
+	expected, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey failed:%s\n", err)
+	}
+	priv, err := LoadPrivateKeySecure(keyFile)
+	if err != nil {
+		t.Fatalf("LoadPrivateKeySecure failed:%s\n", err)
+	}
+	if !priv.Equal(expected) {
+		t.Errorf("LoadPrivateKeySecure returned a different key than LoadPrivateKey\n")
+	}
+	if err := priv.Validate(); err != nil {
+		t.Errorf("Loaded key is invalid, DER zeroized too early?:%s\n", err)
+	}
+	if _, err := LoadPrivateKeySecure(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("Loading a missing file should fail\n")
+	}
+}
+
+func TestZeroizePrivateKey(t *testing.T) {
+	priv := newTestPrivateKey(t)
+	d := priv.D.Bits()
+	msg := []byte("verify after zeroize")
+	sig, err := SignPSSByteArray(priv, Sha256bytes2bytes(msg))
+	if err != nil {
+		t.Fatalf("SignPSSByteArray failed:%s\n", err)
+	}
+	ZeroizePrivateKey(priv)
+	if priv.D.Sign() != 0 || priv.Primes[0].Sign() != 0 || priv.Primes[1].Sign() != 0 || priv.Precomputed.Dp != nil {
+		t.Errorf("Private key material was not zeroized\n")
+	}
+	for i, word := range d {
+		if word != 0 {
+			t.Fatalf("Word %d of D was not overwritten\n", i)
+		}
+	}
+	if err := VerifyPSSByteArray(&priv.PublicKey, sig, msg); err != nil {
+		t.Errorf("Public key should still verify:%s\n", err)
+	}
+	if _, err := SignPSSByteArray(priv, Sha256bytes2bytes(msg)); err == nil {
+		t.Errorf("Signing with a zeroized key should fail\n")
+	}
+	ZeroizePrivateKey(nil)
+}
+
+// EOF